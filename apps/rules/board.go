@@ -6,11 +6,12 @@ import "fmt"
 // NOTE: use NewBoardState to construct these to ensure fields are initialized
 // correctly and that tests are resilient to changes to this type.
 type BoardState struct {
-	Turn   int
-	Height int
-	Width  int
-	Food   []Point
-	Snakes []Snake
+	Turn    int
+	Height  int
+	Width   int
+	Food    []Point
+	Snakes  []Snake
+	Hazards []Point
 
 	// Generic game-level state for maps and rules stages to persist data between turns.
 	GameState map[string]string
@@ -36,6 +37,7 @@ func (p Point) GoString() string {
 
 type Snake struct {
 	ID               string
+	Squad            string
 	Body             []Point
 	Health           int
 	EliminatedCause  string
@@ -51,6 +53,7 @@ func NewBoardState(width, height int) *BoardState {
 		Width:      width,
 		Food:       []Point{},
 		Snakes:     []Snake{},
+		Hazards:    []Point{},
 		GameState:  map[string]string{},
 		PointState: map[Point]int{},
 	}
@@ -64,6 +67,7 @@ func (prevState *BoardState) Clone() *BoardState {
 		Width:      prevState.Width,
 		Food:       append([]Point{}, prevState.Food...),
 		Snakes:     make([]Snake, len(prevState.Snakes)),
+		Hazards:    append([]Point{}, prevState.Hazards...),
 		GameState:  make(map[string]string, len(prevState.GameState)),
 		PointState: make(map[Point]int, len(prevState.PointState)),
 	}
@@ -75,6 +79,7 @@ func (prevState *BoardState) Clone() *BoardState {
 	}
 	for i := 0; i < len(prevState.Snakes); i++ {
 		nextState.Snakes[i].ID = prevState.Snakes[i].ID
+		nextState.Snakes[i].Squad = prevState.Snakes[i].Squad
 		nextState.Snakes[i].Health = prevState.Snakes[i].Health
 		nextState.Snakes[i].Body = append([]Point{}, prevState.Snakes[i].Body...)
 		nextState.Snakes[i].EliminatedCause = prevState.Snakes[i].EliminatedCause
@@ -102,6 +107,12 @@ func (state *BoardState) WithSnakes(snakes []Snake) *BoardState {
 	return state
 }
 
+// Builder method to set Hazards and return the modified BoardState.
+func (state *BoardState) WithHazards(hazards []Point) *BoardState {
+	state.Hazards = hazards
+	return state
+}
+
 // Builder method to set State and return the modified BoardState.
 func (state *BoardState) WithGameState(gameState map[string]string) *BoardState {
 	state.GameState = gameState
@@ -448,7 +459,7 @@ func PlaceFoodFixed(rand Rand, b *BoardState) error {
 
 	// Finally, always place 1 food in center of board for dramatic purposes
 	isCenterOccupied := true
-	unoccupiedPoints := GetUnoccupiedPoints(b, true)
+	unoccupiedPoints := GetUnoccupiedPoints(b, true, false)
 	for _, point := range unoccupiedPoints {
 		if point == centerCoord {
 			isCenterOccupied = false
@@ -466,7 +477,7 @@ func PlaceFoodFixed(rand Rand, b *BoardState) error {
 // PlaceFoodRandomly adds up to n new food to the board in random unoccupied squares
 func PlaceFoodRandomly(rand Rand, b *BoardState, n int) error {
 	for i := 0; i < n; i++ {
-		unoccupiedPoints := GetUnoccupiedPoints(b, false)
+		unoccupiedPoints := GetUnoccupiedPoints(b, false, false)
 		if len(unoccupiedPoints) > 0 {
 			newFood := unoccupiedPoints[rand.Intn(len(unoccupiedPoints))]
 			b.Food = append(b.Food, newFood)
@@ -483,7 +494,7 @@ func absInt(n int) int {
 }
 
 func GetEvenUnoccupiedPoints(b *BoardState) []Point {
-	unoccupiedPoints := GetUnoccupiedPoints(b, true)
+	unoccupiedPoints := GetUnoccupiedPoints(b, true, false)
 	evenUnoccupiedPoints := []Point{}
 
 	for _, point := range unoccupiedPoints {
@@ -507,7 +518,7 @@ func removeCenterCoord(b *BoardState, points []Point) []Point {
 	return noCenterPoints
 }
 
-func GetUnoccupiedPoints(b *BoardState, includePossibleMoves bool) []Point {
+func GetUnoccupiedPoints(b *BoardState, includePossibleMoves bool, includeHazards bool) []Point {
 	pointIsOccupied := map[int]map[int]bool{}
 	for _, p := range b.Food {
 		if _, xExists := pointIsOccupied[p.X]; !xExists {
@@ -516,6 +527,15 @@ func GetUnoccupiedPoints(b *BoardState, includePossibleMoves bool) []Point {
 		pointIsOccupied[p.X][p.Y] = true
 	}
 
+	if includeHazards {
+		for _, p := range b.Hazards {
+			if _, xExists := pointIsOccupied[p.X]; !xExists {
+				pointIsOccupied[p.X] = map[int]bool{}
+			}
+			pointIsOccupied[p.X][p.Y] = true
+		}
+	}
+
 	for _, snake := range b.Snakes {
 		if snake.EliminatedCause != NotEliminated {
 			continue
@@ -3,22 +3,52 @@ package board
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/rs/cors"
 	log "github.com/spf13/jwalterweatherman"
 )
 
-// A minimal server capable of handling the requests from a single browser client running the board viewer.
+// DefaultAddr is the address the board server listens on unless another one is given.
+const DefaultAddr = "127.0.0.1:5000"
+
+const (
+	// writeTimeout is how long a spectator has to accept each event before it is disconnected.
+	writeTimeout = 10 * time.Second
+	// defaultShutdownTimeout is how long Shutdown waits for spectators to be sent every event before disconnecting them.
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+// A server for the board viewer that can host many games at once, each watched by any number of browser clients.
+// Games are served at /games/{id}, and their events are streamed over a websocket at /games/{id}/events.
+// The embedded board viewer is served from the root, see ViewerURL.
 type BoardServer struct {
-	connected bool
-	game      Game
-	events    chan GameEvent // channel for sending events from the game runner to the browser client
-	done      chan bool      // channel for signalling (via closing) that all events have been sent to the browser client
+	addr       string
+	mutex      sync.Mutex
+	games      map[string]*gameStream
+	closed     bool
+	spectators sync.WaitGroup // websocket clients that haven't been sent every event yet
+	sockets    map[*websocket.Conn]bool
 
-	httpServer *http.Server
+	shutdownTimeout time.Duration
+	httpServer      *http.Server
+}
+
+// gameStream is a game and every event sent for it so far, so that spectators who join late see the whole game.
+type gameStream struct {
+	mutex     sync.Mutex
+	cond      *sync.Cond // signalled when an event is added, the game ends or a spectator disconnects
+	game      Game
+	events    []GameEvent
+	ended     bool
+	connected bool
 }
 
 var upgrader = websocket.Upgrader{
@@ -27,14 +57,14 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-func NewBoardServer(game Game) *BoardServer {
+func NewBoardServer(addr string) *BoardServer {
 	mux := http.NewServeMux()
 
 	server := &BoardServer{
-		connected: false,
-		game:      game,
-		events:    make(chan GameEvent, 1000), // buffered channel to allow game to run ahead of browser client
-		done:      make(chan bool),
+		addr:            addr,
+		games:           map[string]*gameStream{},
+		sockets:         map[*websocket.Conn]bool{},
+		shutdownTimeout: defaultShutdownTimeout,
 		httpServer: &http.Server{
 			Handler: cors.New(cors.Options{
 				AllowedOrigins: []string{"*"},
@@ -42,54 +72,203 @@ func NewBoardServer(game Game) *BoardServer {
 		},
 	}
 
-	mux.HandleFunc("/game", server.handleGame)
-	mux.HandleFunc("/game/events", server.handleWebsocket)
+	mux.HandleFunc("/games/", server.handleGames)
+	mux.Handle("/", viewerHandler())
 
 	return server
 }
 
-func (server *BoardServer) handleGame(w http.ResponseWriter, r *http.Request) {
+// AddGame starts hosting a game under the given ID.
+func (server *BoardServer) AddGame(id string, game Game) error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if _, ok := server.games[id]; ok {
+		return fmt.Errorf("game %q is already being served", id)
+	}
+	stream := &gameStream{game: game}
+	stream.cond = sync.NewCond(&stream.mutex)
+	server.games[id] = stream
+	return nil
+}
+
+// SendEvent adds an event to a game, to be sent to all its spectators.
+func (server *BoardServer) SendEvent(id string, event GameEvent) {
+	stream, ok := server.getGame(id)
+	if !ok {
+		log.ERROR.Printf("Unable to send event for unknown game %q", id)
+		return
+	}
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	if stream.ended {
+		log.ERROR.Printf("Unable to send event for game %q after it has ended", id)
+		return
+	}
+	stream.events = append(stream.events, event)
+	stream.cond.Broadcast()
+}
+
+// EndGame marks that no more events will be sent for a game.
+// Spectators are disconnected once they have been sent every event, but late joiners can still watch the game.
+func (server *BoardServer) EndGame(id string) {
+	if stream, ok := server.getGame(id); ok {
+		stream.end()
+	}
+}
+
+// RemoveGame ends a game and stops hosting it. Spectators already watching are still sent the rest of its events.
+func (server *BoardServer) RemoveGame(id string) {
+	server.mutex.Lock()
+	stream, ok := server.games[id]
+	delete(server.games, id)
+	server.mutex.Unlock()
+	if ok {
+		stream.end()
+	}
+}
+
+// IsConnected reports whether a browser client has requested the game.
+func (server *BoardServer) IsConnected(id string) bool {
+	stream, ok := server.getGame(id)
+	if !ok {
+		return false
+	}
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	return stream.connected
+}
+
+func (server *BoardServer) getGame(id string) (*gameStream, bool) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	stream, ok := server.games[id]
+	return stream, ok
+}
+
+func (server *BoardServer) handleGames(w http.ResponseWriter, r *http.Request) {
+	id, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/games/"), "/")
+	switch resource {
+	case "":
+		server.ServeGame(w, r, id)
+	case "events":
+		server.ServeEvents(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// ServeGame responds with the details of a game, so that other servers can serve games from their own routes.
+func (server *BoardServer) ServeGame(w http.ResponseWriter, r *http.Request, id string) {
+	stream, ok := server.getGame(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	server.handleGame(w, stream)
+}
+
+// ServeEvents upgrades the request to a websocket and streams a game's events over it.
+func (server *BoardServer) ServeEvents(w http.ResponseWriter, r *http.Request, id string) {
+	stream, ok := server.getGame(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	server.handleWebsocket(w, r, stream)
+}
+
+func (server *BoardServer) handleGame(w http.ResponseWriter, stream *gameStream) {
+	stream.mutex.Lock()
+	stream.connected = true
+	game := stream.game
+	stream.mutex.Unlock()
+
 	w.Header().Add("Content-Type", "application/json")
 	err := json.NewEncoder(w).Encode(struct {
 		Game Game
-	}{server.game})
+	}{game})
 	if err != nil {
 		log.ERROR.Printf("Unable to serialize game: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	server.connected = true
 }
 
-func (server *BoardServer) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+func (server *BoardServer) handleWebsocket(w http.ResponseWriter, r *http.Request, stream *gameStream) {
+	server.mutex.Lock()
+	if server.closed {
+		server.mutex.Unlock()
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	server.spectators.Add(1)
+	server.mutex.Unlock()
+	defer server.spectators.Done()
+
 	ws, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.ERROR.Printf("Unable to upgrade connection: %v", err)
 		return
 	}
 
+	server.mutex.Lock()
+	server.sockets[ws] = true
+	server.mutex.Unlock()
 	defer func() {
+		server.mutex.Lock()
+		delete(server.sockets, ws)
+		server.mutex.Unlock()
 		err = ws.Close()
 		if err != nil {
 			log.ERROR.Printf("Unable to close websocket stream")
 		}
 	}()
 
-	for event := range server.events {
-		jsonStr, err := json.Marshal(event)
-		if err != nil {
-			log.ERROR.Printf("Unable to serialize event for websocket: %v", err)
+	stream.mutex.Lock()
+	stream.connected = true
+	stream.mutex.Unlock()
+
+	// Read from the websocket so that a spectator closing the board stops us waiting for events to send them
+	var disconnected atomic.Bool
+	go func() {
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				disconnected.Store(true)
+				stream.mutex.Lock()
+				stream.cond.Broadcast()
+				stream.mutex.Unlock()
+				return
+			}
 		}
+	}()
 
-		err = ws.WriteMessage(websocket.TextMessage, jsonStr)
-		if err != nil {
-			log.ERROR.Printf("Unable to write to websocket: %v", err)
+	// Every spectator is sent the events from the start of the game, so late joiners see the whole game
+	sent := 0
+	for {
+		events, ended := stream.waitForEvents(sent, disconnected.Load)
+		if disconnected.Load() {
+			log.DEBUG.Printf("Websocket client disconnected")
+			return
+		}
+		if len(events) == 0 && ended {
 			break
 		}
-	}
+		for _, event := range events {
+			jsonStr, err := json.Marshal(event)
+			if err != nil {
+				log.ERROR.Printf("Unable to serialize event for websocket: %v", err)
+			}
 
-	log.DEBUG.Printf("Finished writing all game events, signalling game server to stop")
-	close(server.done)
+			// Spectators that stop reading are disconnected rather than holding up the server
+			_ = ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+			err = ws.WriteMessage(websocket.TextMessage, jsonStr)
+			if err != nil {
+				log.ERROR.Printf("Unable to write to websocket: %v", err)
+				return
+			}
+		}
+		sent += len(events)
+	}
 
 	log.DEBUG.Printf("Sending websocket close message")
 	err = ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
@@ -98,8 +277,26 @@ func (server *BoardServer) handleWebsocket(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// waitForEvents blocks until there are events after the first sent, the game ends or stop returns true.
+// It returns the new events and whether the game has ended.
+func (stream *gameStream) waitForEvents(sent int, stop func() bool) ([]GameEvent, bool) {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	for sent >= len(stream.events) && !stream.ended && !stop() {
+		stream.cond.Wait()
+	}
+	return stream.events[sent:], stream.ended
+}
+
+func (stream *gameStream) end() {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	stream.ended = true
+	stream.cond.Broadcast()
+}
+
 func (server *BoardServer) Listen() (string, error) {
-	listener, err := net.Listen("tcp", "127.0.0.1:5000")
+	listener, err := net.Listen("tcp", server.addr)
 	if err != nil {
 		return "", err
 	}
@@ -115,11 +312,34 @@ func (server *BoardServer) Listen() (string, error) {
 	return url, nil
 }
 
+// Shutdown ends every game, waits for connected spectators to be sent all of their events and stops the server.
+// Spectators that are still being sent events after the shutdown timeout are disconnected.
 func (server *BoardServer) Shutdown() {
-	close(server.events)
+	server.mutex.Lock()
+	server.closed = true
+	for _, stream := range server.games {
+		stream.end()
+	}
+	server.mutex.Unlock()
 
 	log.INFO.Printf("Waiting for websocket clients to finish")
-	<-server.done
+	finished := make(chan struct{})
+	go func() {
+		server.spectators.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(server.shutdownTimeout):
+		log.WARN.Printf("Disconnecting websocket clients that haven't finished after %v", server.shutdownTimeout)
+		server.mutex.Lock()
+		for ws := range server.sockets {
+			// Closing the connection fails any write in progress, so the spectator's handler returns
+			ws.Close()
+		}
+		server.mutex.Unlock()
+		<-finished
+	}
 	log.INFO.Printf("Server is done, exiting")
 
 	err := server.httpServer.Shutdown(context.Background())
@@ -127,11 +347,3 @@ func (server *BoardServer) Shutdown() {
 		log.ERROR.Printf("Error shutting down HTTP server: %v", err)
 	}
 }
-
-func (server *BoardServer) SendEvent(event GameEvent) {
-	server.events <- event
-}
-
-func (server *BoardServer) IsConnected() bool {
-	return server.connected
-}
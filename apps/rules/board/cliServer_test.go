@@ -0,0 +1,164 @@
+package board
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func startTestServer(t *testing.T) (*BoardServer, string) {
+	server := NewBoardServer("127.0.0.1:0")
+	serverURL, err := server.Listen()
+	require.NoError(t, err)
+	return server, serverURL
+}
+
+func frameEvent(turn int) GameEvent {
+	return GameEvent{EventType: EVENT_TYPE_FRAME, Data: GameFrame{Turn: turn}}
+}
+
+// watchGame connects a spectator to a game and returns the turns of the frames it is sent until the game ends.
+func watchGame(t *testing.T, serverURL, id string) <-chan []int {
+	ws, _, err := websocket.DefaultDialer.Dial(strings.Replace(serverURL, "http", "ws", 1)+"/games/"+id+"/events", nil)
+	require.NoError(t, err)
+
+	turns := make(chan []int, 1)
+	go func() {
+		defer ws.Close()
+		received := []int{}
+		for {
+			_, message, err := ws.ReadMessage()
+			if err != nil {
+				turns <- received
+				return
+			}
+			var event struct {
+				Data GameFrame
+			}
+			if json.Unmarshal(message, &event) == nil {
+				received = append(received, event.Data.Turn)
+			}
+		}
+	}()
+	return turns
+}
+
+func TestBoardServerGames(t *testing.T) {
+	server, serverURL := startTestServer(t)
+	require.NoError(t, server.AddGame("one", Game{Width: 7}))
+	require.NoError(t, server.AddGame("two", Game{Width: 11}))
+	require.EqualError(t, server.AddGame("one", Game{}), `game "one" is already being served`)
+	require.False(t, server.IsConnected("one"))
+
+	res, err := http.Get(serverURL + "/games/two")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var body struct{ Game Game }
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	require.Equal(t, 11, body.Game.Width)
+	require.True(t, server.IsConnected("two"))
+	require.False(t, server.IsConnected("one"))
+
+	for _, path := range []string{"/games/three", "/games/one/other", "/game"} {
+		res, err := http.Get(serverURL + path)
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode, path)
+	}
+
+	// Removed games are no longer served, and their IDs can be used again
+	server.RemoveGame("two")
+	res, err = http.Get(serverURL + "/games/two")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+	require.NoError(t, server.AddGame("two", Game{}))
+
+	server.Shutdown()
+}
+
+func TestBoardServerSpectators(t *testing.T) {
+	server, serverURL := startTestServer(t)
+	require.NoError(t, server.AddGame("one", Game{}))
+	require.NoError(t, server.AddGame("two", Game{}))
+
+	first := watchGame(t, serverURL, "one")
+	second := watchGame(t, serverURL, "one")
+	other := watchGame(t, serverURL, "two")
+	require.Eventually(t, func() bool { return server.IsConnected("one") }, time.Second, 10*time.Millisecond)
+
+	server.SendEvent("one", frameEvent(0))
+	server.SendEvent("two", frameEvent(5))
+	server.SendEvent("one", frameEvent(1))
+
+	// Spectators joining late are sent the game from the start
+	late := watchGame(t, serverURL, "one")
+	server.SendEvent("one", frameEvent(2))
+	server.EndGame("one")
+
+	require.Equal(t, []int{0, 1, 2}, <-first)
+	require.Equal(t, []int{0, 1, 2}, <-second)
+	require.Equal(t, []int{0, 1, 2}, <-late)
+
+	// Finished games can still be watched
+	require.Equal(t, []int{0, 1, 2}, <-watchGame(t, serverURL, "one"))
+
+	server.Shutdown()
+	require.Equal(t, []int{5}, <-other)
+}
+
+func TestBoardServerSpectatorDisconnects(t *testing.T) {
+	server, serverURL := startTestServer(t)
+	require.NoError(t, server.AddGame("one", Game{}))
+
+	ws, _, err := websocket.DefaultDialer.Dial(strings.Replace(serverURL, "http", "ws", 1)+"/games/one/events", nil)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return server.IsConnected("one") }, time.Second, 10*time.Millisecond)
+	require.NoError(t, ws.Close())
+
+	// Shutdown doesn't wait for events to be sent to spectators that have gone
+	done := make(chan bool)
+	go func() {
+		server.SendEvent("one", frameEvent(0))
+		server.Shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server didn't shut down")
+	}
+}
+
+func TestBoardServerShutdownDisconnectsStalledSpectators(t *testing.T) {
+	server, serverURL := startTestServer(t)
+	server.shutdownTimeout = 100 * time.Millisecond
+	require.NoError(t, server.AddGame("one", Game{}))
+
+	// The spectator never reads, so sending it more than fits in the socket buffers blocks
+	ws, _, err := websocket.DefaultDialer.Dial(strings.Replace(serverURL, "http", "ws", 1)+"/games/one/events", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+	require.Eventually(t, func() bool { return server.IsConnected("one") }, time.Second, 10*time.Millisecond)
+	padding := strings.Repeat("x", 64*1024)
+	for i := 0; i < 512; i++ {
+		server.SendEvent("one", GameEvent{EventType: EVENT_TYPE_FRAME, Data: padding})
+	}
+
+	done := make(chan bool)
+	go func() {
+		server.Shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server didn't shut down")
+	}
+}
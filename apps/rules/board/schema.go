@@ -35,9 +35,10 @@ type GameEvent struct {
 
 // Represents a single turn in the game.
 type GameFrame struct {
-	Turn   int           `json:"Turn"`
-	Snakes []Snake       `json:"Snakes"`
-	Food   []rules.Point `json:"Food"`
+	Turn    int           `json:"Turn"`
+	Snakes  []Snake       `json:"Snakes"`
+	Food    []rules.Point `json:"Food"`
+	Hazards []rules.Point `json:"Hazards"`
 }
 
 type GameEnd struct {
@@ -47,6 +48,7 @@ type GameEnd struct {
 type Snake struct {
 	ID            string        `json:"ID"`
 	Name          string        `json:"Name"`
+	Squad         string        `json:"Squad"`
 	Body          []rules.Point `json:"Body"`
 	Health        int           `json:"Health"`
 	Death         *Death        `json:"Death"`
@@ -0,0 +1,26 @@
+package board
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/url"
+)
+
+// The board viewer is embedded so that games can be watched without an internet connection.
+//
+//go:embed viewer
+var viewerFiles embed.FS
+
+func viewerHandler() http.Handler {
+	files, err := fs.Sub(viewerFiles, "viewer")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServer(http.FS(files))
+}
+
+// ViewerURL returns the URL of the embedded board viewer for a game on the server at serverURL.
+func ViewerURL(serverURL string, gameID string) string {
+	return serverURL + "/?game=" + url.QueryEscape(gameID)
+}
@@ -0,0 +1,259 @@
+// A minimal board viewer for games served by the Battlesnake CLI.
+// Usage: /?game=<id>, optionally with &engine=<url> to watch a game served from another board server.
+(function () {
+  "use strict";
+
+  const params = new URLSearchParams(window.location.search);
+  const gameID = params.get("game");
+  const engine = (params.get("engine") || window.location.origin).replace(/\/$/, "");
+
+  const canvas = document.getElementById("board");
+  const ctx = canvas.getContext("2d");
+  const scrubber = document.getElementById("scrubber");
+  const playButton = document.getElementById("play");
+  const speedSelect = document.getElementById("speed");
+  const statusText = document.getElementById("status");
+  const snakeList = document.getElementById("snakes");
+
+  const cellSize = 32;
+  const defaultColor = "#888888";
+
+  let game = null;
+  const frames = [];
+  let current = 0;
+  let playing = true;
+  let ended = false;
+  let timer = null;
+
+  function setStatus(text) {
+    statusText.textContent = text;
+  }
+
+  async function start() {
+    if (!gameID) {
+      setStatus("No game given, open this page with ?game=<id>");
+      return;
+    }
+
+    let res;
+    try {
+      res = await fetch(`${engine}/games/${encodeURIComponent(gameID)}`);
+    } catch (err) {
+      setStatus(`Unable to reach the board server: ${err}`);
+      return;
+    }
+    if (!res.ok) {
+      setStatus(`Game ${gameID} wasn't found`);
+      return;
+    }
+    game = (await res.json()).Game;
+
+    document.getElementById("title").textContent = `${game.RulesetName || "standard"} on ${game.Map || "standard"}`;
+    canvas.width = game.Width * cellSize;
+    canvas.height = game.Height * cellSize;
+    drawGrid();
+
+    const ws = new WebSocket(`${engine.replace(/^http/, "ws")}/games/${encodeURIComponent(gameID)}/events`);
+    ws.onmessage = (message) => onEvent(JSON.parse(message.data));
+    ws.onclose = () => {
+      ended = true;
+      render();
+    };
+    schedule();
+  }
+
+  function onEvent(event) {
+    if (event.Type === "frame") {
+      frames.push(event.Data);
+      scrubber.max = frames.length - 1;
+      if (frames.length === 1) {
+        render();
+      }
+    } else if (event.Type === "game_end") {
+      ended = true;
+      render();
+    }
+  }
+
+  function schedule() {
+    clearInterval(timer);
+    timer = setInterval(() => {
+      if (playing && current < frames.length - 1) {
+        show(current + 1);
+      }
+    }, 1000 / Number(speedSelect.value));
+  }
+
+  function show(index) {
+    current = Math.max(0, Math.min(index, frames.length - 1));
+    render();
+  }
+
+  function setPlaying(value) {
+    playing = value;
+    playButton.innerHTML = playing ? "&#x23F8;" : "&#x25B6;&#xFE0E;";
+  }
+
+  // Board coordinates have y = 0 at the bottom, the canvas has it at the top
+  function cellX(point) {
+    return point.X * cellSize;
+  }
+
+  function cellY(point) {
+    return (game.Height - 1 - point.Y) * cellSize;
+  }
+
+  function drawGrid() {
+    ctx.clearRect(0, 0, canvas.width, canvas.height);
+    ctx.fillStyle = "#323644";
+    for (let x = 0; x < game.Width; x++) {
+      for (let y = 0; y < game.Height; y++) {
+        ctx.fillRect(x * cellSize + 1, y * cellSize + 1, cellSize - 2, cellSize - 2);
+      }
+    }
+  }
+
+  function drawSnake(snake) {
+    const color = snake.Color || defaultColor;
+    ctx.fillStyle = color;
+    ctx.strokeStyle = color;
+    ctx.lineWidth = cellSize * 0.6;
+    ctx.lineCap = "round";
+    ctx.lineJoin = "round";
+
+    // Join segments that are next to each other, segments that wrap around the board aren't joined
+    ctx.beginPath();
+    snake.Body.forEach((point, i) => {
+      const x = cellX(point) + cellSize / 2;
+      const y = cellY(point) + cellSize / 2;
+      const prev = snake.Body[i - 1];
+      if (i === 0 || Math.abs(prev.X - point.X) + Math.abs(prev.Y - point.Y) !== 1) {
+        ctx.moveTo(x, y);
+      }
+      ctx.lineTo(x, y);
+    });
+    ctx.stroke();
+
+    const head = snake.Body[0];
+    if (head) {
+      ctx.fillRect(cellX(head) + 3, cellY(head) + 3, cellSize - 6, cellSize - 6);
+      ctx.fillStyle = "#1c1e26";
+      ctx.beginPath();
+      ctx.arc(cellX(head) + cellSize / 2, cellY(head) + cellSize / 2, cellSize / 8, 0, 2 * Math.PI);
+      ctx.fill();
+    }
+  }
+
+  function render() {
+    if (!game) {
+      return;
+    }
+    if (frames.length === 0) {
+      setStatus("Waiting for the game to start");
+      return;
+    }
+
+    const frame = frames[current];
+    scrubber.value = current;
+    drawGrid();
+
+    ctx.fillStyle = "rgba(0, 0, 0, 0.45)";
+    (frame.Hazards || []).forEach((point) => {
+      ctx.fillRect(cellX(point), cellY(point), cellSize, cellSize);
+    });
+
+    ctx.fillStyle = "#ff5c75";
+    (frame.Food || []).forEach((point) => {
+      ctx.beginPath();
+      ctx.arc(cellX(point) + cellSize / 2, cellY(point) + cellSize / 2, cellSize / 4, 0, 2 * Math.PI);
+      ctx.fill();
+    });
+
+    frame.Snakes.filter((snake) => !snake.Death).forEach(drawSnake);
+
+    const isLast = current === frames.length - 1;
+    setStatus(`Turn ${frame.Turn}` + (ended && isLast ? ", game over" : isLast && !ended ? ", live" : ""));
+    renderSnakes(frame);
+  }
+
+  function renderSnakes(frame) {
+    snakeList.replaceChildren(
+      ...frame.Snakes.map((snake) => {
+        const item = document.createElement("li");
+        const color = snake.Color || defaultColor;
+
+        const swatch = document.createElement("span");
+        swatch.className = "swatch";
+        swatch.style.background = color;
+
+        const name = document.createElement("strong");
+        name.textContent = snake.Name || snake.ID;
+
+        const length = document.createElement("span");
+        length.textContent = snake.Death ? "" : `${snake.Body.length}`;
+        length.title = "Length";
+
+        const details = document.createElement("span");
+        details.className = "details";
+        if (snake.Death) {
+          item.className = "eliminated";
+          const by = snake.Death.EliminatedBy ? ` by ${snake.Death.EliminatedBy}` : "";
+          details.textContent = `Eliminated on turn ${snake.Death.Turn}: ${snake.Death.Cause}${by}`;
+        } else {
+          details.textContent = `Health ${snake.Health}, latency ${snake.Latency || 0}ms` + (snake.Squad ? `, squad ${snake.Squad}` : "");
+        }
+
+        const health = document.createElement("div");
+        health.className = "health";
+        const bar = document.createElement("div");
+        bar.style.width = `${snake.Death ? 0 : snake.Health}%`;
+        bar.style.background = color;
+        health.appendChild(bar);
+
+        item.append(swatch, name, length, details, health);
+        return item;
+      })
+    );
+  }
+
+  document.getElementById("first").onclick = () => {
+    setPlaying(false);
+    show(0);
+  };
+  document.getElementById("prev").onclick = () => {
+    setPlaying(false);
+    show(current - 1);
+  };
+  document.getElementById("next").onclick = () => {
+    setPlaying(false);
+    show(current + 1);
+  };
+  document.getElementById("last").onclick = () => show(frames.length - 1);
+  playButton.onclick = () => {
+    if (!playing && current === frames.length - 1 && ended) {
+      show(0);
+    }
+    setPlaying(!playing);
+  };
+  scrubber.oninput = () => {
+    setPlaying(false);
+    show(Number(scrubber.value));
+  };
+  speedSelect.onchange = schedule;
+
+  document.addEventListener("keydown", (event) => {
+    const actions = {
+      ArrowLeft: "prev",
+      ArrowRight: "next",
+      Home: "first",
+      End: "last",
+      " ": "play",
+    };
+    if (actions[event.key]) {
+      event.preventDefault();
+      document.getElementById(actions[event.key]).click();
+    }
+  });
+
+  start();
+})();
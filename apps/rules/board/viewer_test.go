@@ -0,0 +1,43 @@
+package board
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewerIsServed(t *testing.T) {
+	server, serverURL := startTestServer(t)
+	defer server.Shutdown()
+
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{"/", "text/html", `<script src="board.js">`},
+		{"/?game=one", "text/html", `<canvas id="board">`},
+		{"/board.js", "javascript", "/games/"},
+		{"/board.css", "text/css", "canvas"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			res, err := http.Get(serverURL + test.path)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			require.Contains(t, res.Header.Get("Content-Type"), test.contentType)
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			require.Contains(t, string(body), test.contains)
+		})
+	}
+}
+
+func TestViewerURL(t *testing.T) {
+	require.Equal(t, "http://127.0.0.1:5000/?game=abc-123", ViewerURL("http://127.0.0.1:5000", "abc-123"))
+	require.Equal(t, "http://127.0.0.1:5000/?game=a+b%26c", ViewerURL("http://127.0.0.1:5000", "a b&c"))
+}
@@ -18,6 +18,7 @@ func TestBoardStateClone(t *testing.T) {
 		WithSnakes([]Snake{
 			{
 				ID:               "1",
+				Squad:            "red",
 				Body:             []Point{{X: 1, Y: 2}},
 				Health:           99,
 				EliminatedCause:  EliminatedByCollision,
@@ -25,6 +26,7 @@ func TestBoardStateClone(t *testing.T) {
 				EliminatedBy:     "2",
 			},
 		}).
+		WithHazards([]Point{{X: 1, Y: 3}}).
 		WithGameState(map[string]string{"example": "game data"}).
 		WithPointState(map[Point]int{{X: 1, Y: 1}: 42})
 
@@ -276,7 +278,7 @@ func TestPlaceSnakesDefault(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprint(test.BoardState.Width, test.BoardState.Height, len(test.SnakeIDs)), func(t *testing.T) {
-			require.Equal(t, test.BoardState.Width*test.BoardState.Height, len(GetUnoccupiedPoints(test.BoardState, true)))
+			require.Equal(t, test.BoardState.Width*test.BoardState.Height, len(GetUnoccupiedPoints(test.BoardState, true, false)))
 			err := PlaceSnakesAutomatically(MaxRand, test.BoardState, test.SnakeIDs)
 			require.Equal(t, test.Err, err, "Snakes: %d", len(test.BoardState.Snakes))
 			if err == nil {
@@ -793,15 +795,17 @@ func TestGetUnoccupiedPoints(t *testing.T) {
 		},
 		{
 			&BoardState{
-				Height: 1,
-				Width:  1,
+				Height:  1,
+				Width:   1,
+				Hazards: []Point{{X: 0, Y: 0}},
 			},
 			[]Point{},
 		},
 		{
 			&BoardState{
-				Height: 2,
-				Width:  2,
+				Height:  2,
+				Width:   2,
+				Hazards: []Point{{X: 1, Y: 1}},
 			},
 			[]Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 0}},
 		},
@@ -820,12 +824,16 @@ func TestGetUnoccupiedPoints(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		unoccupiedPoints := GetUnoccupiedPoints(test.Board, true)
+		unoccupiedPoints := GetUnoccupiedPoints(test.Board, true, true)
 		require.Equal(t, len(test.Expected), len(unoccupiedPoints))
 		for i, e := range test.Expected {
 			require.Equal(t, e, unoccupiedPoints[i])
 		}
 	}
+
+	// Hazards are only treated as occupied when asked for
+	board := &BoardState{Height: 1, Width: 1, Hazards: []Point{{X: 0, Y: 0}}}
+	require.Equal(t, []Point{{X: 0, Y: 0}}, GetUnoccupiedPoints(board, true, false))
 }
 
 func TestGetEvenUnoccupiedPoints(t *testing.T) {
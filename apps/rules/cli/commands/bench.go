@@ -0,0 +1,372 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"rules"
+
+	"github.com/spf13/cobra"
+	log "github.com/spf13/jwalterweatherman"
+)
+
+// eliminationCauses lists the causes of death in the order they are reported.
+var eliminationCauses = []string{
+	rules.EliminatedByCollision,
+	rules.EliminatedBySelfCollision,
+	rules.EliminatedByHeadToHeadCollision,
+	rules.EliminatedByOutOfBounds,
+	rules.EliminatedByOutOfHealth,
+	rules.EliminatedByHazard,
+	rules.EliminatedBySquad,
+}
+
+type benchOptions struct {
+	Games     int
+	Parallel  int
+	OutputDir string
+	JSON      bool
+}
+
+// benchReport summarises many games played between the same snakes.
+type benchReport struct {
+	Games        int                `json:"games"`
+	Seed         int64              `json:"seed"`
+	Draws        int                `json:"draws"`
+	AverageTurns float64            `json:"averageTurns"`
+	MinTurns     int                `json:"minTurns"`
+	MaxTurns     int                `json:"maxTurns"`
+	Snakes       []benchSnakeReport `json:"snakes"`
+}
+
+// benchSnakeReport is how one snake did over every game, with a 95% confidence interval for its win rate.
+type benchSnakeReport struct {
+	Name         string         `json:"name"`
+	Wins         int            `json:"wins"`
+	Draws        int            `json:"draws"`
+	Losses       int            `json:"losses"`
+	WinRate      float64        `json:"winRate"`
+	WinRateLow   float64        `json:"winRateLow"`
+	WinRateHigh  float64        `json:"winRateHigh"`
+	AveragePlace float64        `json:"averagePlace"`
+	Deaths       map[string]int `json:"deaths"`
+	Latency      latencyReport  `json:"latencyMs"`
+	Timeouts     int            `json:"timeouts"`
+	InvalidMoves int            `json:"invalidMoves"`
+}
+
+// latencyReport is the distribution of a snake's response times in milliseconds.
+type latencyReport struct {
+	P50 int64 `json:"p50"`
+	P90 int64 `json:"p90"`
+	P99 int64 `json:"p99"`
+	Max int64 `json:"max"`
+}
+
+// bench plays the same game many times with different seeds.
+type bench struct {
+	opts     benchOptions
+	template GameState
+
+	// playGame plays a single game and returns its result, stopping early if ctx is cancelled. It is replaced in tests
+	playGame   func(ctx context.Context, seed int64, logPath string) (*result, error)
+	httpClient TimedHttpClient
+}
+
+func NewBenchCommand() *cobra.Command {
+	opts := benchOptions{}
+	gameState := &GameState{}
+
+	var benchCmd = &cobra.Command{
+		Use:   "bench",
+		Short: "Play many games between the same snakes and report how they did.",
+		Long:  "Play many games between the same snakes with different seeds, and report their win rates with 95% confidence intervals, game lengths, causes of death and response time percentiles.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gameState.sizeChanged = boardSizeChanged(cmd)
+			if gameState.Seed == 0 {
+				gameState.Seed = time.Now().UTC().UnixNano()
+			}
+			b, err := newBench(*gameState, opts)
+			if err != nil {
+				return err
+			}
+
+			log.INFO.Printf("Playing %d games between %s with seed %d", opts.Games, strings.Join(gameState.Names, ", "), gameState.Seed)
+			report, err := b.run()
+			if err != nil {
+				return err
+			}
+			if opts.JSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+			return printBenchReport(cmd.OutOrStdout(), report)
+		},
+	}
+
+	benchCmd.Flags().IntVar(&opts.Games, "games", 100, "Number of games to play")
+	benchCmd.Flags().IntVar(&opts.Parallel, "parallel", 1, "Number of games to play at the same time")
+	benchCmd.Flags().StringVarP(&opts.OutputDir, "output", "o", "", "Directory to write a log of every game to")
+	benchCmd.Flags().BoolVar(&opts.JSON, "json", false, "Print the report as JSON")
+
+	addSnakeFlags(benchCmd, gameState)
+	addGameFlags(benchCmd, gameState)
+
+	benchCmd.Flags().SortFlags = false
+
+	return benchCmd
+}
+
+func newBench(template GameState, opts benchOptions) (*bench, error) {
+	if len(template.URLs) == 0 {
+		return nil, errors.New("at least one snake URL must be given")
+	}
+	if len(template.Names) != len(template.URLs) {
+		return nil, fmt.Errorf("every snake needs a name to tell them apart in the report, got %d names for %d URLs", len(template.Names), len(template.URLs))
+	}
+	names := map[string]bool{}
+	for _, name := range template.Names {
+		if names[name] {
+			return nil, fmt.Errorf("snake names must be unique, %q is used more than once", name)
+		}
+		names[name] = true
+	}
+	if opts.Games < 1 {
+		return nil, fmt.Errorf("games must be at least 1, got %d", opts.Games)
+	}
+	if opts.Parallel < 1 {
+		return nil, fmt.Errorf("parallel must be at least 1, got %d", opts.Parallel)
+	}
+
+	b := &bench{opts: opts, template: template}
+	b.playGame = b.runGame
+	return b, nil
+}
+
+// run plays every game and builds the report. Game i is played with the seed plus i, so a bench can be run again
+// with the same seed to play the same games. If a game fails, no more games are started and the games being
+// played are stopped.
+func (b *bench) run() (benchReport, error) {
+	results := make([]*result, b.opts.Games)
+	i := 0
+	err := runParallel(context.Background(), b.opts.Parallel, func() (func(context.Context) error, error) {
+		if i == len(results) {
+			return nil, nil
+		}
+		n := i
+		i++
+		return func(ctx context.Context) error {
+			res, err := b.play(ctx, n)
+			results[n] = res
+			return err
+		}, nil
+	})
+	if err != nil {
+		return benchReport{}, err
+	}
+
+	return buildBenchReport(b.template.Names, b.template.Seed, results), nil
+}
+
+// play plays the nth game of the bench and logs its result.
+func (b *bench) play(ctx context.Context, n int) (*result, error) {
+	seed := b.template.Seed + int64(n)
+	logPath := ""
+	if b.opts.OutputDir != "" {
+		logPath = filepath.Join(b.opts.OutputDir, fmt.Sprintf("%d.jsonl", seed))
+	}
+	res, err := b.playGame(ctx, seed, logPath)
+	if err != nil {
+		return nil, fmt.Errorf("error playing game with seed %d: %w", seed, err)
+	}
+	if res.IsDraw {
+		log.INFO.Printf("Finished game %d of %d: draw", n+1, b.opts.Games)
+	} else {
+		log.INFO.Printf("Finished game %d of %d: %s won", n+1, b.opts.Games, res.WinnerName)
+	}
+	return res, nil
+}
+
+// runGame plays a game between the bench's snakes with the given seed, stopping early if ctx is cancelled.
+func (b *bench) runGame(ctx context.Context, seed int64, logPath string) (*result, error) {
+	template := b.template
+	template.ctx = ctx
+	return runHeadlessGame(template, template.Names, template.URLs, seed, logPath, b.httpClient)
+}
+
+func buildBenchReport(names []string, seed int64, results []*result) benchReport {
+	report := benchReport{Games: len(results), Seed: seed, MinTurns: math.MaxInt}
+	snakes := map[string]*benchSnakeReport{}
+	latencies := map[string][]time.Duration{}
+	places := map[string]int{}
+	for _, name := range names {
+		snakes[name] = &benchSnakeReport{Name: name, Deaths: map[string]int{}}
+	}
+
+	totalTurns := 0
+	for _, res := range results {
+		if res.IsDraw {
+			report.Draws++
+		}
+		turns := gameLength(res.Placements)
+		totalTurns += turns
+		if turns < report.MinTurns {
+			report.MinTurns = turns
+		}
+		if turns > report.MaxTurns {
+			report.MaxTurns = turns
+		}
+
+		for _, placement := range res.Placements {
+			snake, ok := snakes[placement.Name]
+			if !ok {
+				continue
+			}
+			switch {
+			case !res.IsDraw && placement.Name == res.WinnerName:
+				snake.Wins++
+			case res.IsDraw && placement.Place == 1:
+				snake.Draws++
+			default:
+				snake.Losses++
+			}
+			places[placement.Name] += placement.Place
+			if placement.EliminatedCause != rules.NotEliminated {
+				snake.Deaths[placement.EliminatedCause]++
+			}
+			snake.Timeouts += placement.Stats.Timeouts
+			snake.InvalidMoves += placement.Stats.InvalidMoves
+			latencies[placement.Name] = append(latencies[placement.Name], placement.Stats.latencies...)
+		}
+	}
+	if len(results) > 0 {
+		report.AverageTurns = float64(totalTurns) / float64(len(results))
+	} else {
+		report.MinTurns = 0
+	}
+
+	for _, name := range names {
+		snake := snakes[name]
+		games := snake.Wins + snake.Draws + snake.Losses
+		if games > 0 {
+			snake.WinRate = float64(snake.Wins) / float64(games)
+			snake.AveragePlace = float64(places[name]) / float64(games)
+		}
+		snake.WinRateLow, snake.WinRateHigh = wilsonInterval(snake.Wins, games)
+		snake.Latency = latencyPercentiles(latencies[name])
+		report.Snakes = append(report.Snakes, *snake)
+	}
+	return report
+}
+
+// gameLength is the number of turns in a game, taken from the last turn any snake was on the board.
+func gameLength(placements []Placement) int {
+	turns := 0
+	for _, placement := range placements {
+		last := placement.Stats.TurnsSurvived
+		if placement.EliminatedCause != rules.NotEliminated {
+			last = placement.EliminatedOnTurn
+		}
+		if last > turns {
+			turns = last
+		}
+	}
+	return turns
+}
+
+// wilsonInterval is the 95% Wilson score interval for a proportion, which holds up for small numbers of games
+// and win rates close to 0 or 1.
+func wilsonInterval(successes, trials int) (float64, float64) {
+	if trials == 0 {
+		return 0, 1
+	}
+	const z = 1.96
+	n := float64(trials)
+	p := float64(successes) / n
+	center := (p + z*z/(2*n)) / (1 + z*z/n)
+	margin := z / (1 + z*z/n) * math.Sqrt(p*(1-p)/n+z*z/(4*n*n))
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// latencyPercentiles uses the nearest rank method, so every percentile is a response time that was measured.
+func latencyPercentiles(latencies []time.Duration) latencyReport {
+	if len(latencies) == 0 {
+		return latencyReport{}
+	}
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p float64) int64 {
+		rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		}
+		return sorted[rank-1].Milliseconds()
+	}
+	return latencyReport{
+		P50: percentile(50),
+		P90: percentile(90),
+		P99: percentile(99),
+		Max: sorted[len(sorted)-1].Milliseconds(),
+	}
+}
+
+func printBenchReport(w io.Writer, report benchReport) error {
+	fmt.Fprintf(w, "%d games with seeds %d to %d, %d draws\n", report.Games, report.Seed, report.Seed+int64(report.Games)-1, report.Draws)
+	fmt.Fprintf(w, "Game length: %.1f turns on average, %d to %d\n\n", report.AverageTurns, report.MinTurns, report.MaxTurns)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tWINS\tDRAWS\tLOSSES\tWIN RATE\t95% CI\tAVG PLACE\tP50 MS\tP90 MS\tP99 MS\tMAX MS\tTIMEOUTS\tINVALID")
+	for _, s := range report.Snakes {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.1f%%\t%.1f%% - %.1f%%\t%.2f\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			s.Name, s.Wins, s.Draws, s.Losses, s.WinRate*100, s.WinRateLow*100, s.WinRateHigh*100, s.AveragePlace,
+			s.Latency.P50, s.Latency.P90, s.Latency.P99, s.Latency.Max, s.Timeouts, s.InvalidMoves)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// Causes of death are listed in a fixed order, followed by any others in alphabetical order
+	causes := append([]string{}, eliminationCauses...)
+	known := map[string]bool{}
+	for _, cause := range causes {
+		known[cause] = true
+	}
+	var others []string
+	for _, s := range report.Snakes {
+		for cause := range s.Deaths {
+			if !known[cause] {
+				known[cause] = true
+				others = append(others, cause)
+			}
+		}
+	}
+	sort.Strings(others)
+	causes = append(causes, others...)
+
+	fmt.Fprintln(w, "\nCauses of death:")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "CAUSE")
+	for _, s := range report.Snakes {
+		fmt.Fprintf(tw, "\t%s", s.Name)
+	}
+	fmt.Fprintln(tw)
+	for _, cause := range causes {
+		fmt.Fprintf(tw, "%s", cause)
+		for _, s := range report.Snakes {
+			fmt.Fprintf(tw, "\t%d", s.Deaths[cause])
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"rules"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBenchReport(t *testing.T) {
+	ms := time.Millisecond
+	results := []*result{
+		{
+			WinnerName: "one",
+			Placements: []Placement{
+				{Place: 1, Name: "one", Stats: SnakeStats{TurnsSurvived: 20, latencies: []time.Duration{10 * ms, 20 * ms}}},
+				{Place: 2, Name: "two", EliminatedCause: rules.EliminatedByCollision, EliminatedOnTurn: 20, Stats: SnakeStats{Timeouts: 1, latencies: []time.Duration{400 * ms}}},
+			},
+		},
+		{
+			WinnerName: "two",
+			Placements: []Placement{
+				{Place: 1, Name: "two", Stats: SnakeStats{TurnsSurvived: 40, latencies: []time.Duration{50 * ms}}},
+				{Place: 2, Name: "one", EliminatedCause: rules.EliminatedByOutOfBounds, EliminatedOnTurn: 31, Stats: SnakeStats{InvalidMoves: 2, latencies: []time.Duration{30 * ms}}},
+			},
+		},
+		{
+			IsDraw: true,
+			Placements: []Placement{
+				{Place: 1, Name: "one", EliminatedCause: rules.EliminatedByHeadToHeadCollision, EliminatedOnTurn: 6},
+				{Place: 1, Name: "two", EliminatedCause: rules.EliminatedByHeadToHeadCollision, EliminatedOnTurn: 6},
+			},
+		},
+	}
+
+	report := buildBenchReport([]string{"one", "two"}, 10, results)
+	require.Equal(t, 3, report.Games)
+	require.Equal(t, int64(10), report.Seed)
+	require.Equal(t, 1, report.Draws)
+	require.Equal(t, 22.0, report.AverageTurns)
+	require.Equal(t, 6, report.MinTurns)
+	require.Equal(t, 40, report.MaxTurns)
+
+	require.Len(t, report.Snakes, 2)
+	one, two := report.Snakes[0], report.Snakes[1]
+	require.Equal(t, "one", one.Name)
+	require.Equal(t, []int{1, 1, 1}, []int{one.Wins, one.Draws, one.Losses})
+	require.InDelta(t, 1.0/3, one.WinRate, 0.0001)
+	require.Less(t, one.WinRateLow, one.WinRate)
+	require.Greater(t, one.WinRateHigh, one.WinRate)
+	require.InDelta(t, 4.0/3, one.AveragePlace, 0.0001)
+	require.Equal(t, map[string]int{rules.EliminatedByOutOfBounds: 1, rules.EliminatedByHeadToHeadCollision: 1}, one.Deaths)
+	require.Equal(t, latencyReport{P50: 20, P90: 30, P99: 30, Max: 30}, one.Latency)
+	require.Equal(t, 2, one.InvalidMoves)
+
+	require.Equal(t, "two", two.Name)
+	require.Equal(t, []int{1, 1, 1}, []int{two.Wins, two.Draws, two.Losses})
+	require.Equal(t, map[string]int{rules.EliminatedByCollision: 1, rules.EliminatedByHeadToHeadCollision: 1}, two.Deaths)
+	require.Equal(t, latencyReport{P50: 50, P90: 400, P99: 400, Max: 400}, two.Latency)
+	require.Equal(t, 1, two.Timeouts)
+}
+
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		successes, trials int
+		low, high         float64
+	}{
+		{50, 100, 0.4038, 0.5962},
+		{0, 10, 0, 0.2775},
+		{10, 10, 0.7225, 1},
+		{450, 500, 0.8706, 0.9233},
+		{0, 0, 0, 1},
+	}
+	for _, test := range tests {
+		low, high := wilsonInterval(test.successes, test.trials)
+		require.InDelta(t, test.low, low, 0.0001)
+		require.InDelta(t, test.high, high, 0.0001)
+	}
+}
+
+func TestLatencyPercentiles(t *testing.T) {
+	require.Equal(t, latencyReport{}, latencyPercentiles(nil))
+
+	latencies := []time.Duration{}
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, latencyReport{P50: 50, P90: 90, P99: 99, Max: 100}, latencyPercentiles(latencies))
+	require.Equal(t, 100*time.Millisecond, latencies[0], "the latencies passed in aren't reordered")
+}
+
+func TestNewBenchErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		urls  []string
+		opts  benchOptions
+		err   string
+	}{
+		{"no snakes", nil, nil, benchOptions{Games: 1, Parallel: 1}, "at least one snake URL must be given"},
+		{"missing names", []string{"one"}, []string{"http://one", "http://two"}, benchOptions{Games: 1, Parallel: 1}, "every snake needs a name to tell them apart in the report, got 1 names for 2 URLs"},
+		{"duplicate names", []string{"one", "one"}, []string{"http://one", "http://two"}, benchOptions{Games: 1, Parallel: 1}, `snake names must be unique, "one" is used more than once`},
+		{"no games", []string{"one"}, []string{"http://one"}, benchOptions{Parallel: 1}, "games must be at least 1, got 0"},
+		{"parallel", []string{"one"}, []string{"http://one"}, benchOptions{Games: 1}, "parallel must be at least 1, got 0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newBench(GameState{Names: test.names, URLs: test.urls}, test.opts)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestBenchRunsGames(t *testing.T) {
+	outputDir := t.TempDir()
+	template := *buildDefaultGameState()
+	template.Names = []string{"one", "two"}
+	template.URLs = []string{"http://one.example.com", "http://two.example.com"}
+	template.MaxTurns = 3
+	template.Seed = 40
+	b, err := newBench(template, benchOptions{Games: 4, Parallel: 2, OutputDir: outputDir})
+	require.NoError(t, err)
+	b.httpClient = stubHTTPClient{nil, 200, func(_ string) string { return `{"move": "down"}` }, 5 * time.Millisecond}
+
+	report, err := b.run()
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Games)
+	for _, snake := range report.Snakes {
+		require.Equal(t, 4, snake.Wins+snake.Draws+snake.Losses)
+		require.Equal(t, int64(5), snake.Latency.P50)
+	}
+	for seed := 40; seed < 44; seed++ {
+		_, err := os.Stat(filepath.Join(outputDir, fmt.Sprintf("%d.jsonl", seed)))
+		require.NoError(t, err)
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printBenchReport(out, report))
+	require.Contains(t, out.String(), "4 games with seeds 40 to 43")
+	require.Contains(t, out.String(), "Causes of death:")
+	require.Contains(t, out.String(), rules.EliminatedBySelfCollision)
+}
+
+func TestBenchStopsAfterError(t *testing.T) {
+	template := GameState{Names: []string{"one", "two"}, URLs: []string{"http://one.example.com", "http://two.example.com"}}
+	b, err := newBench(template, benchOptions{Games: 10, Parallel: 2})
+	require.NoError(t, err)
+
+	var mutex sync.Mutex
+	played := 0
+	b.playGame = func(ctx context.Context, seed int64, logPath string) (*result, error) {
+		mutex.Lock()
+		played++
+		mutex.Unlock()
+		if seed == 0 {
+			return nil, errors.New("snake crashed")
+		}
+		// Games in progress are stopped once another game fails
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	_, err = b.run()
+	require.EqualError(t, err, "error playing game with seed 0: snake crashed")
+	require.Equal(t, 2, played)
+}
@@ -0,0 +1,371 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"rules"
+	"rules/client"
+
+	"github.com/spf13/cobra"
+)
+
+const debugHelp = `Commands:
+  n, next            step forward one turn (or just press enter)
+  b, back            step back one turn
+  j, jump <turn>     jump to a turn, playing the game forward if needed
+  p, print           draw the board again
+  i, inspect [snake] show the /move requests and responses for the current turn
+  r, resend <snake>  send the current turn's /move request to a snake again
+  h, help            show this help
+  q, quit            stop debugging
+Snakes can be referred to by their label (A, B, C...), name or ID.
+`
+
+// moveExchange is a /move request sent to a snake and the response it gave.
+type moveExchange struct {
+	Request    []byte
+	Response   []byte
+	StatusCode int
+	Latency    time.Duration
+	Error      error
+}
+
+// recordingHTTPClient keeps the body of each /move request and response so they can be inspected,
+// keyed by the ID of the snake the request was for.
+type recordingHTTPClient struct {
+	TimedHttpClient
+	mutex     *sync.Mutex
+	exchanges map[string]moveExchange
+}
+
+func newRecordingHTTPClient(httpClient TimedHttpClient) recordingHTTPClient {
+	return recordingHTTPClient{
+		TimedHttpClient: httpClient,
+		mutex:           &sync.Mutex{},
+		exchanges:       map[string]moveExchange{},
+	}
+}
+
+func (recorder recordingHTTPClient) Post(url string, contentType string, body io.Reader) (*http.Response, time.Duration, error) {
+	requestBody, err := io.ReadAll(body)
+	if err != nil {
+		return nil, 0, err
+	}
+	res, latency, err := recorder.TimedHttpClient.Post(url, contentType, bytes.NewBuffer(requestBody))
+
+	exchange := moveExchange{Request: requestBody, Latency: latency, Error: err}
+	if res != nil {
+		exchange.StatusCode = res.StatusCode
+		if res.Body != nil {
+			// Replace the body so that the caller can still read it
+			exchange.Response, _ = io.ReadAll(res.Body)
+			res.Body.Close()
+			res.Body = io.NopCloser(bytes.NewBuffer(exchange.Response))
+		}
+	}
+
+	snakeRequest := client.SnakeRequest{}
+	if json.Unmarshal(requestBody, &snakeRequest) == nil && strings.HasSuffix(url, "/move") {
+		recorder.mutex.Lock()
+		recorder.exchanges[snakeRequest.You.ID] = exchange
+		recorder.mutex.Unlock()
+	}
+
+	return res, latency, err
+}
+
+func (recorder recordingHTTPClient) WithTimeout(timeout time.Duration) TimedHttpClient {
+	return recordingHTTPClient{
+		TimedHttpClient: recorder.TimedHttpClient.WithTimeout(timeout),
+		mutex:           recorder.mutex,
+		exchanges:       recorder.exchanges,
+	}
+}
+
+// take returns and forgets the exchanges recorded since it was last called.
+func (recorder recordingHTTPClient) take() map[string]moveExchange {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	exchanges := map[string]moveExchange{}
+	for id, exchange := range recorder.exchanges {
+		exchanges[id] = exchange
+		delete(recorder.exchanges, id)
+	}
+	return exchanges
+}
+
+// debugTurn is a board state in the debugger's history, along with the /move exchanges
+// that were used to play the turn after it once it has been played.
+type debugTurn struct {
+	boardState  *rules.BoardState
+	snakeStates map[string]SnakeState
+	gameOver    bool
+	exchanges   map[string]moveExchange
+}
+
+// debugger steps through a game under the control of commands read from in.
+type debugger struct {
+	gameState *GameState
+	recorder  recordingHTTPClient
+	history   []debugTurn
+	current   int
+	in        *bufio.Scanner
+	out       io.Writer
+}
+
+func NewDebugCommand() *cobra.Command {
+	gameState := &GameState{}
+
+	var debugCmd = &cobra.Command{
+		Use:   "debug",
+		Short: "Step through a game of Battlesnake turn by turn.",
+		Long:  "Run a game of Battlesnake locally one turn at a time, stepping backwards and forwards and inspecting the requests sent to each snake.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gameState.sizeChanged = boardSizeChanged(cmd)
+			if err := gameState.Initialize(); err != nil {
+				return fmt.Errorf("error initializing game: %w", err)
+			}
+			return newDebugger(gameState, os.Stdin, os.Stdout).Run()
+		},
+	}
+
+	addSnakeFlags(debugCmd, gameState)
+	addGameFlags(debugCmd, gameState)
+	debugCmd.Flags().BoolVar(&gameState.RenderColor, "color", false, "Use the snakes' colours when drawing the board")
+
+	debugCmd.Flags().SortFlags = false
+
+	return debugCmd
+}
+
+func newDebugger(gameState *GameState, in io.Reader, out io.Writer) *debugger {
+	recorder := newRecordingHTTPClient(gameState.httpClient)
+	gameState.httpClient = recorder
+	return &debugger{
+		gameState: gameState,
+		recorder:  recorder,
+		in:        bufio.NewScanner(in),
+		out:       out,
+	}
+}
+
+// Run sets up the game and then handles commands until told to quit or the input ends.
+func (d *debugger) Run() error {
+	var err error
+	d.gameState.snakeStates, err = d.gameState.buildSnakesFromOptions()
+	if err != nil {
+		return fmt.Errorf("error getting snake metadata: %w", err)
+	}
+	gameOver, boardState, err := d.gameState.initializeBoardFromArgs()
+	if err != nil {
+		return fmt.Errorf("error initializing board: %w", err)
+	}
+	d.recorder.take()
+	d.history = []debugTurn{{boardState: boardState, snakeStates: copySnakeStates(d.gameState.snakeStates), gameOver: gameOver}}
+
+	fmt.Fprintf(d.out, "Debugging game with seed %d, type h for help\n", d.gameState.Seed)
+	d.print()
+
+	for {
+		fmt.Fprintf(d.out, "turn %d> ", d.turn().boardState.Turn)
+		if !d.in.Scan() {
+			fmt.Fprintln(d.out)
+			return d.in.Err()
+		}
+		quit, err := d.handleCommand(d.in.Text())
+		if err != nil {
+			fmt.Fprintf(d.out, "Error: %v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+func (d *debugger) handleCommand(line string) (bool, error) {
+	fields := strings.Fields(line)
+	command, arg := "n", ""
+	if len(fields) > 0 {
+		command = fields[0]
+	}
+	if len(fields) > 1 {
+		arg = fields[1]
+	}
+
+	switch command {
+	case "n", "next":
+		if err := d.step(); err != nil {
+			return false, err
+		}
+		d.print()
+	case "b", "back":
+		if d.current == 0 {
+			return false, fmt.Errorf("already at the start of the game")
+		}
+		d.current--
+		d.print()
+	case "j", "jump":
+		turn, err := strconv.Atoi(arg)
+		if err != nil || turn < 0 {
+			return false, fmt.Errorf("jump needs a turn number")
+		}
+		if err := d.jump(turn); err != nil {
+			return false, err
+		}
+		d.print()
+	case "p", "print":
+		d.print()
+	case "i", "inspect":
+		return false, d.inspect(arg)
+	case "r", "resend":
+		return false, d.resend(arg)
+	case "h", "help":
+		fmt.Fprint(d.out, debugHelp)
+	case "q", "quit":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q, type h for help", command)
+	}
+	return false, nil
+}
+
+func (d *debugger) turn() *debugTurn {
+	return &d.history[d.current]
+}
+
+// step moves forward a turn, playing it if it isn't in the history yet.
+func (d *debugger) step() error {
+	if d.current < len(d.history)-1 {
+		d.current++
+		return nil
+	}
+
+	turn := d.turn()
+	if turn.gameOver {
+		return fmt.Errorf("the game is over")
+	}
+
+	d.gameState.snakeStates = copySnakeStates(turn.snakeStates)
+	// The board is cloned so the states in the history are never changed by later turns
+	gameOver, boardState, err := d.gameState.createNextBoardState(turn.boardState.Clone())
+	if err != nil {
+		return err
+	}
+	turn.exchanges = d.recorder.take()
+
+	if gameOver {
+		// The game over stages don't play a turn, so the current turn is the last one
+		turn.gameOver = true
+		winner, isDraw := d.gameState.determineWinner(turn.boardState)
+		for _, snake := range turn.boardState.Snakes {
+			d.gameState.sendEndRequest(turn.boardState, d.gameState.snakeStates[snake.ID])
+		}
+		d.recorder.take()
+		if isDraw {
+			return fmt.Errorf("the game is over, it was a draw")
+		}
+		return fmt.Errorf("the game is over, %s was the winner", winner.Name)
+	}
+
+	d.history = append(d.history, debugTurn{boardState: boardState, snakeStates: copySnakeStates(d.gameState.snakeStates)})
+	d.current++
+	return nil
+}
+
+func (d *debugger) jump(turn int) error {
+	for d.turn().boardState.Turn < turn {
+		if err := d.step(); err != nil {
+			return err
+		}
+	}
+	for d.turn().boardState.Turn > turn {
+		d.current--
+	}
+	return nil
+}
+
+func (d *debugger) print() {
+	d.gameState.snakeStates = d.turn().snakeStates
+	d.gameState.renderBoard(d.out, d.turn().boardState)
+}
+
+// findSnake looks up a snake on the current board by label, name or ID.
+func (d *debugger) findSnake(ref string) (rules.Snake, bool) {
+	for i, snake := range d.turn().boardState.Snakes {
+		snakeState := d.turn().snakeStates[snake.ID]
+		if strings.EqualFold(ref, snakeLabel(i)) || ref == snakeState.Name || ref == snake.ID {
+			return snake, true
+		}
+	}
+	return rules.Snake{}, false
+}
+
+func (d *debugger) inspect(ref string) error {
+	turn := d.turn()
+	if turn.exchanges == nil {
+		return fmt.Errorf("turn %d hasn't been played yet, step forward first", turn.boardState.Turn)
+	}
+
+	for i, snake := range turn.boardState.Snakes {
+		if ref != "" {
+			if found, ok := d.findSnake(ref); !ok {
+				return fmt.Errorf("no snake called %q", ref)
+			} else if found.ID != snake.ID {
+				continue
+			}
+		}
+		exchange, ok := turn.exchanges[snake.ID]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(d.out, "%s %s\n", snakeLabel(i), turn.snakeStates[snake.ID].Name)
+		d.printExchange(exchange)
+	}
+	return nil
+}
+
+func (d *debugger) resend(ref string) error {
+	snake, ok := d.findSnake(ref)
+	if !ok {
+		return fmt.Errorf("no snake called %q", ref)
+	}
+	if snake.EliminatedCause != rules.NotEliminated {
+		return fmt.Errorf("%s has been eliminated", ref)
+	}
+
+	d.gameState.snakeStates = d.turn().snakeStates
+	snakeState := d.gameState.getSnakeUpdate(d.turn().boardState, d.turn().snakeStates[snake.ID])
+	exchange := d.recorder.take()[snake.ID]
+	d.printExchange(exchange)
+	if snakeState.Error == nil && !snakeState.InvalidMove && snakeState.StatusCode == http.StatusOK {
+		fmt.Fprintf(d.out, "Move: %s\n", snakeState.LastMove)
+	}
+	return nil
+}
+
+func (d *debugger) printExchange(exchange moveExchange) {
+	fmt.Fprintf(d.out, "Request:\n%s\n", exchange.Request)
+	if exchange.Error != nil {
+		fmt.Fprintf(d.out, "Error after %dms: %v\n", exchange.Latency.Milliseconds(), exchange.Error)
+		return
+	}
+	fmt.Fprintf(d.out, "Response (status %d, %dms):\n%s\n", exchange.StatusCode, exchange.Latency.Milliseconds(), exchange.Response)
+}
+
+func copySnakeStates(snakeStates map[string]SnakeState) map[string]SnakeState {
+	copied := make(map[string]SnakeState, len(snakeStates))
+	for id, snakeState := range snakeStates {
+		copied[id] = snakeState
+	}
+	return copied
+}
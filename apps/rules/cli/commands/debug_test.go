@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func buildDebugGameState(t *testing.T) *GameState {
+	gameState := buildDefaultGameState()
+	gameState.Names = []string{"one", "two"}
+	gameState.URLs = []string{"http://one.example.com", "http://two.example.com"}
+	gameState.MaxTurns = 3
+	require.NoError(t, gameState.Initialize())
+	gameState.httpClient = stubHTTPClient{nil, 200, func(_ string) string { return `{"move": "down"}` }, time.Millisecond}
+	return gameState
+}
+
+func TestDebuggerCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		turn     int
+	}{
+		{
+			name:     "enter steps forward",
+			input:    "\n\n",
+			expected: []string{"Debugging game with seed 1", "Turn 0\n", "Turn 1\n", "Turn 2\n"},
+			turn:     2,
+		},
+		{
+			name:     "step back",
+			input:    "n\nn\nb\n",
+			expected: []string{"turn 2> Turn 1\n"},
+			turn:     1,
+		},
+		{
+			name:     "step back at start",
+			input:    "b\n",
+			expected: []string{"Error: already at the start of the game"},
+			turn:     0,
+		},
+		{
+			name:     "jump forward and back",
+			input:    "j 2\nj 1\n",
+			expected: []string{"turn 0> Turn 2\n", "turn 2> Turn 1\n"},
+			turn:     1,
+		},
+		{
+			name:     "jump past the end",
+			input:    "j 10\n",
+			expected: []string{"Error: the game is over"},
+			turn:     3,
+		},
+		{
+			name:     "inspect",
+			input:    "n\nb\ni\n",
+			expected: []string{"A one\nRequest:\n{", "B two\nRequest:\n{", `Response (status 200, 1ms):` + "\n" + `{"move": "down"}`},
+			turn:     0,
+		},
+		{
+			name:     "inspect unplayed turn",
+			input:    "i\n",
+			expected: []string{"Error: turn 0 hasn't been played yet"},
+			turn:     0,
+		},
+		{
+			name:     "resend",
+			input:    "r b\n",
+			expected: []string{`"name":"two"`, "Move: down"},
+			turn:     0,
+		},
+		{
+			name:     "resend unknown snake",
+			input:    "r three\n",
+			expected: []string{`Error: no snake called "three"`},
+			turn:     0,
+		},
+		{
+			name:     "unknown command",
+			input:    "x\nq\nn\n",
+			expected: []string{`Error: unknown command "x"`},
+			turn:     0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			d := newDebugger(buildDebugGameState(t), strings.NewReader(test.input), out)
+
+			require.NoError(t, d.Run())
+			for _, expected := range test.expected {
+				require.Contains(t, out.String(), expected)
+			}
+			require.Equal(t, test.turn, d.turn().boardState.Turn)
+		})
+	}
+}
+
+func TestDebuggerHistoryIsUnchanged(t *testing.T) {
+	d := newDebugger(buildDebugGameState(t), strings.NewReader("n\n"), &bytes.Buffer{})
+	require.NoError(t, d.Run())
+
+	start := d.history[0].boardState.Clone()
+	require.NoError(t, d.jump(3))
+	require.Equal(t, start, d.history[0].boardState)
+	for turn, debugTurn := range d.history {
+		require.Equal(t, turn, debugTurn.boardState.Turn)
+	}
+}
+
+func TestRecordingHTTPClient(t *testing.T) {
+	recorder := newRecordingHTTPClient(stubHTTPClient{nil, 200, func(url string) string { return url }, time.Millisecond})
+	timedRecorder := recorder.WithTimeout(time.Second)
+
+	res, _, err := timedRecorder.Post("http://example.com/move", "application/json", strings.NewReader(`{"you":{"id":"one"}}`))
+	require.NoError(t, err)
+	body := &bytes.Buffer{}
+	_, err = body.ReadFrom(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "http://example.com/move", body.String())
+
+	_, _, err = recorder.Post("http://example.com/end", "application/json", strings.NewReader(`{"you":{"id":"two"}}`))
+	require.NoError(t, err)
+
+	exchanges := recorder.take()
+	require.Len(t, exchanges, 1)
+	require.Equal(t, `{"you":{"id":"one"}}`, string(exchanges["one"].Request))
+	require.Equal(t, "http://example.com/move", string(exchanges["one"].Response))
+	require.Equal(t, 200, exchanges["one"].StatusCode)
+	require.Empty(t, recorder.take())
+}
@@ -13,28 +13,84 @@ type GameExporter struct {
 	snakeRequests []client.SnakeRequest
 	winner        SnakeState
 	isDraw        bool
+	seed          int64
+	placements    []Placement
+
+	// Streaming state, used by FlushToFile to only write new lines
+	isFinished      bool
+	headerFlushed   bool
+	requestsFlushed int
+	resultFlushed   bool
+}
+
+// Placement records where a snake finished a game and how it played.
+type Placement struct {
+	Place            int        `json:"place"`
+	ID               string     `json:"id"`
+	Name             string     `json:"name"`
+	Squad            string     `json:"squad,omitempty"`
+	EliminatedCause  string     `json:"eliminatedCause,omitempty"`
+	EliminatedBy     string     `json:"eliminatedBy,omitempty"`
+	EliminatedOnTurn int        `json:"eliminatedOnTurn,omitempty"`
+	Stats            SnakeStats `json:"stats"`
+	Moves            []string   `json:"moves,omitempty"`
 }
 
 type result struct {
-	WinnerID   string `json:"winnerId"`
-	WinnerName string `json:"winnerName"`
-	IsDraw     bool   `json:"isDraw"`
+	WinnerID   string      `json:"winnerId"`
+	WinnerName string      `json:"winnerName"`
+	IsDraw     bool        `json:"isDraw"`
+	Seed       int64       `json:"seed"`
+	Placements []Placement `json:"placements"`
 }
 
+// FlushToFile writes the lines of the game log that haven't been written yet, and returns how many were written.
+// It can be called after every turn to stream the log, the result line is only written once Finish has been called.
 func (ge *GameExporter) FlushToFile(outputFile io.Writer) (int, error) {
-	formattedOutput, err := ge.ConvertToJSON(false)
-	if err != nil {
-		return 0, err
+	var values []interface{}
+	if !ge.headerFlushed {
+		values = append(values, ge.game)
+	}
+	for _, snakeRequest := range ge.snakeRequests[ge.requestsFlushed:] {
+		values = append(values, snakeRequest)
+	}
+	if ge.isFinished && !ge.resultFlushed {
+		values = append(values, ge.result())
 	}
 
-	for _, line := range formattedOutput {
-		_, err := io.WriteString(outputFile, fmt.Sprintf("%s\n", line))
+	for i, value := range values {
+		line, err := json.Marshal(value)
 		if err != nil {
-			return 0, err
+			return i, err
+		}
+		if _, err := io.WriteString(outputFile, fmt.Sprintf("%s\n", line)); err != nil {
+			return i, err
 		}
 	}
 
-	return len(formattedOutput), nil
+	ge.headerFlushed = true
+	ge.requestsFlushed = len(ge.snakeRequests)
+	ge.resultFlushed = ge.isFinished
+
+	return len(values), nil
+}
+
+// Finish records the result of the game.
+func (ge *GameExporter) Finish(winner SnakeState, isDraw bool, placements []Placement) {
+	ge.winner = winner
+	ge.isDraw = isDraw
+	ge.placements = placements
+	ge.isFinished = true
+}
+
+func (ge *GameExporter) result() result {
+	return result{
+		WinnerID:   ge.winner.ID,
+		WinnerName: ge.winner.Name,
+		IsDraw:     ge.isDraw,
+		Seed:       ge.seed,
+		Placements: ge.placements,
+	}
 }
 
 func (ge *GameExporter) ConvertToJSON(onlyLastFrame bool) ([]string, error) {
@@ -66,11 +122,7 @@ func (ge *GameExporter) ConvertToJSON(onlyLastFrame bool) ([]string, error) {
 	}
 
 	if !onlyLastFrame {
-		serialisedResult, err := json.Marshal(result{
-			WinnerID:   ge.winner.ID,
-			WinnerName: ge.winner.Name,
-			IsDraw:     ge.isDraw,
-		})
+		serialisedResult, err := json.Marshal(ge.result())
 		if err != nil {
 			return output, err
 		}
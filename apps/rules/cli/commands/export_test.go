@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"rules/client"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameExporterFlushToFile(t *testing.T) {
+	ge := GameExporter{
+		game:          client.Game{ID: "game-id", Timeout: 500},
+		snakeRequests: make([]client.SnakeRequest, 0),
+		seed:          7,
+	}
+	var buf bytes.Buffer
+
+	ge.AddSnakeRequest(client.SnakeRequest{Turn: 0})
+	n, err := ge.FlushToFile(&buf)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	// Only the new turns are written on later flushes
+	ge.AddSnakeRequest(client.SnakeRequest{Turn: 1})
+	ge.AddSnakeRequest(client.SnakeRequest{Turn: 2})
+	n, err = ge.FlushToFile(&buf)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	ge.Finish(SnakeState{ID: "one", Name: "One"}, false, []Placement{{Place: 1, ID: "one", Name: "One"}})
+	n, err = ge.FlushToFile(&buf)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	n, err = ge.FlushToFile(&buf)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+	require.Contains(t, lines[0], `"id":"game-id"`)
+	require.Contains(t, lines[1], `"turn":0`)
+	require.Contains(t, lines[3], `"turn":2`)
+	require.True(t, strings.HasPrefix(lines[4], `{"winnerId":"one","winnerName":"One","isDraw":false,"seed":7,"placements":[{"place":1`))
+
+	// Streaming produces the same log as converting the whole game at once
+	converted, err := ge.ConvertToJSON(false)
+	require.NoError(t, err)
+	require.Equal(t, converted, lines)
+}
@@ -9,6 +9,7 @@ import (
 type TimedHttpClient interface {
 	Get(url string) (*http.Response, time.Duration, error)
 	Post(url string, contentType string, body io.Reader) (*http.Response, time.Duration, error)
+	WithTimeout(timeout time.Duration) TimedHttpClient
 }
 
 type timedHTTPClient struct {
@@ -26,3 +27,10 @@ func (client timedHTTPClient) Post(url string, contentType string, body io.Reade
 	res, err := client.Client.Post(url, contentType, body)
 	return res, time.Since(startTime), err
 }
+
+// WithTimeout returns a copy of the client that gives up on requests after the given timeout.
+func (client timedHTTPClient) WithTimeout(timeout time.Duration) TimedHttpClient {
+	c := *client.Client
+	c.Timeout = timeout
+	return timedHTTPClient{&c}
+}
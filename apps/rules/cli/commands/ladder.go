@@ -0,0 +1,302 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"rules/ladder"
+
+	"github.com/spf13/cobra"
+	log "github.com/spf13/jwalterweatherman"
+)
+
+type ladderRunOptions struct {
+	LadderPath      string
+	Players         int
+	Games           int
+	Parallel        int
+	OutputDir       string
+	LeaderboardPath string
+}
+
+// ladderRunner plays games between the snakes on a ladder and records their results.
+type ladderRunner struct {
+	opts     ladderRunOptions
+	template GameState
+	ladder   *ladder.Ladder
+	rand     *rand.Rand
+
+	// playGame plays a single game and returns its placements, it is replaced in tests
+	playGame   func(snakes []ladder.Snake, seed int64, logPath string) ([]Placement, error)
+	httpClient TimedHttpClient
+
+	mutex sync.Mutex // held while recording a result, so the ladder file is saved in the order games finish
+}
+
+func NewLadderCommand() *cobra.Command {
+	var ladderPath string
+
+	var ladderCmd = &cobra.Command{
+		Use:   "ladder",
+		Short: "Rate snakes over many games with Elo or Glicko-2.",
+		Long:  "Keep a ladder of registered snakes, play games between them and rate them with Elo or Glicko-2 from where they placed in each game.",
+	}
+	ladderCmd.PersistentFlags().StringVarP(&ladderPath, "file", "f", "ladder.json", "Path to the ladder file, which stores the registered snakes and their ratings")
+
+	var algorithm string
+	var participant Participant
+	var participantsPath string
+	var registerCmd = &cobra.Command{
+		Use:   "register",
+		Short: "Register snakes on the ladder, creating the ladder if it doesn't exist.",
+		Long:  "Register a snake on the ladder, or every snake in a participants file. Registering a snake again updates its URL and keeps its rating, so register new versions of a snake under a new name to compare them.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			participants := []Participant{participant}
+			if participantsPath != "" {
+				var err error
+				if participants, err = loadParticipants(participantsPath); err != nil {
+					return err
+				}
+			} else if participant.Name == "" || participant.URL == "" {
+				return errors.New("a name and URL, or a participants file, must be given")
+			}
+
+			l, err := loadOrCreateLadder(ladderPath, algorithm)
+			if err != nil {
+				return err
+			}
+			for _, p := range participants {
+				if err := l.Register(p.Name, p.URL, p.Author); err != nil {
+					return err
+				}
+				log.INFO.Printf("Registered %s at %s", p.Name, p.URL)
+			}
+			return l.Save(ladderPath)
+		},
+	}
+	registerCmd.Flags().StringVarP(&participant.Name, "name", "n", "", "Name of the snake")
+	registerCmd.Flags().StringVarP(&participant.URL, "url", "u", "", "URL of the snake")
+	registerCmd.Flags().StringVar(&participant.Author, "author", "", "Author of the snake")
+	registerCmd.Flags().StringVarP(&participantsPath, "participants", "p", "", "Path to a YAML or JSON participants file to register every snake from, as used by the tournament command")
+	registerCmd.Flags().StringVar(&algorithm, "algorithm", ladder.AlgorithmGlicko2, "Rating algorithm used when creating a new ladder: elo or glicko2")
+	registerCmd.Flags().SortFlags = false
+
+	opts := ladderRunOptions{}
+	gameState := &GameState{}
+	var runCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Play games between the snakes on the ladder and update their ratings.",
+		Long:  "Play games between randomly picked snakes on the ladder, updating their ratings and saving the ladder after every game. Runs until the number of games is reached or it is interrupted.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.LadderPath = ladderPath
+			l, err := ladder.Load(ladderPath)
+			if err != nil {
+				return fmt.Errorf("unable to load ladder, register snakes first: %w", err)
+			}
+			gameState.sizeChanged = boardSizeChanged(cmd)
+			if gameState.Seed == 0 {
+				gameState.Seed = time.Now().UTC().UnixNano()
+			}
+
+			runner, err := newLadderRunner(l, *gameState, opts)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			log.INFO.Printf("Running %s ladder with %d snakes and seed %d", l.Algorithm, len(l.Snakes), gameState.Seed)
+			if err := runner.run(ctx); err != nil {
+				return err
+			}
+			return printLeaderboard(cmd.OutOrStdout(), l.Leaderboard())
+		},
+	}
+	runCmd.Flags().IntVarP(&opts.Players, "players", "p", 2, "Number of snakes in each game")
+	runCmd.Flags().IntVar(&opts.Games, "games", 0, "Number of games to play (0 to play until interrupted)")
+	runCmd.Flags().IntVar(&opts.Parallel, "parallel", 1, "Number of games to play at the same time")
+	runCmd.Flags().StringVarP(&opts.OutputDir, "output", "o", "", "Directory to write a log of every game to")
+	runCmd.Flags().StringVar(&opts.LeaderboardPath, "leaderboard", "", "Path to write the leaderboard JSON to after every game")
+	addGameFlags(runCmd, gameState)
+	runCmd.Flags().SortFlags = false
+
+	var leaderboardJSON bool
+	var leaderboardCmd = &cobra.Command{
+		Use:   "leaderboard",
+		Short: "Show the snakes on the ladder ranked by rating.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			l, err := ladder.Load(ladderPath)
+			if err != nil {
+				return err
+			}
+			if leaderboardJSON {
+				return writeLeaderboardJSON(cmd.OutOrStdout(), l.Leaderboard())
+			}
+			return printLeaderboard(cmd.OutOrStdout(), l.Leaderboard())
+		},
+	}
+	leaderboardCmd.Flags().BoolVar(&leaderboardJSON, "json", false, "Print the leaderboard as JSON")
+
+	ladderCmd.AddCommand(registerCmd, runCmd, leaderboardCmd)
+	return ladderCmd
+}
+
+// loadOrCreateLadder loads the ladder at path, or creates an empty one if there isn't a file there yet.
+func loadOrCreateLadder(path string, algorithm string) (*ladder.Ladder, error) {
+	l, err := ladder.Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.INFO.Printf("Creating %s ladder at %s", algorithm, path)
+		return ladder.New(algorithm)
+	}
+	return l, err
+}
+
+func newLadderRunner(l *ladder.Ladder, template GameState, opts ladderRunOptions) (*ladderRunner, error) {
+	if opts.Players < 2 {
+		return nil, fmt.Errorf("players must be at least 2, got %d", opts.Players)
+	}
+	if len(l.Snakes) < opts.Players {
+		return nil, fmt.Errorf("the ladder needs at least %d snakes, %d are registered", opts.Players, len(l.Snakes))
+	}
+	if opts.Games < 0 {
+		return nil, fmt.Errorf("games must not be negative, got %d", opts.Games)
+	}
+	if opts.Parallel < 1 {
+		return nil, fmt.Errorf("parallel must be at least 1, got %d", opts.Parallel)
+	}
+
+	runner := &ladderRunner{
+		opts:     opts,
+		template: template,
+		ladder:   l,
+		rand:     rand.New(rand.NewSource(template.Seed)),
+	}
+	runner.playGame = runner.runGame
+	return runner, nil
+}
+
+// run plays games until opts.Games have been played or ctx is cancelled, and then waits for the games in progress.
+// Games are numbered on from the games already on the ladder, which keeps game seeds and log names unique
+// when a ladder is run again.
+func (r *ladderRunner) run(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		log.INFO.Printf("Stopping once the games being played have finished")
+	})
+	defer stop()
+
+	first := r.ladder.Games + 1
+	n := first
+	return runParallel(ctx, r.opts.Parallel, func() (func(context.Context) error, error) {
+		if r.opts.Games != 0 && n == first+r.opts.Games {
+			return nil, nil
+		}
+		snakes, err := r.ladder.Schedule(r.rand, r.opts.Players)
+		if err != nil {
+			return nil, err
+		}
+		game := n
+		n++
+		// Games are left to finish when the ladder is stopped, so that their results are recorded
+		return func(context.Context) error {
+			return r.play(game, snakes)
+		}, nil
+	})
+}
+
+// play plays game number n, records its result on the ladder and saves the ladder.
+func (r *ladderRunner) play(n int, snakes []ladder.Snake) error {
+	names := make([]string, 0, len(snakes))
+	for _, snake := range snakes {
+		names = append(names, snake.Name)
+	}
+	logPath := ""
+	if r.opts.OutputDir != "" {
+		logPath = filepath.Join(r.opts.OutputDir, "games", fmt.Sprintf("%d.jsonl", n))
+	}
+
+	log.INFO.Printf("Playing game %d: %s", n, strings.Join(names, ", "))
+	placements, err := r.playGame(snakes, r.template.Seed+int64(n), logPath)
+	if err != nil {
+		return fmt.Errorf("error playing game %d: %w", n, err)
+	}
+
+	results := make([]ladder.Result, 0, len(placements))
+	for _, placement := range placements {
+		results = append(results, ladder.Result{Name: placement.Name, Place: placement.Place})
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.ladder.Record(results); err != nil {
+		return fmt.Errorf("error recording game %d: %w", n, err)
+	}
+	if err := r.ladder.Save(r.opts.LadderPath); err != nil {
+		return fmt.Errorf("error saving ladder: %w", err)
+	}
+	if r.opts.LeaderboardPath != "" {
+		if err := r.writeLeaderboard(); err != nil {
+			return fmt.Errorf("error writing leaderboard: %w", err)
+		}
+	}
+
+	for _, result := range results {
+		snake, _ := r.ladder.Snake(result.Name)
+		log.INFO.Printf("Game %d: %s placed %d, rated %.0f", n, result.Name, result.Place, snake.Rating.Rating)
+	}
+	return nil
+}
+
+// runGame plays a game between snakes scheduled from the ladder and returns where each of them placed.
+func (r *ladderRunner) runGame(snakes []ladder.Snake, seed int64, logPath string) ([]Placement, error) {
+	names := make([]string, 0, len(snakes))
+	urls := make([]string, 0, len(snakes))
+	for _, snake := range snakes {
+		names = append(names, snake.Name)
+		urls = append(urls, snake.URL)
+	}
+	res, err := runHeadlessGame(r.template, names, urls, seed, logPath, r.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return res.Placements, nil
+}
+
+func (r *ladderRunner) writeLeaderboard() error {
+	f, err := os.Create(r.opts.LeaderboardPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return writeLeaderboardJSON(f, r.ladder.Leaderboard())
+}
+
+func writeLeaderboardJSON(w io.Writer, entries []ladder.Entry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+func printLeaderboard(w io.Writer, entries []ladder.Entry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tNAME\tAUTHOR\tRATING\tDEVIATION\tGAMES\tWINS")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%.0f\t%.0f\t%d\t%d\n", e.Rank, e.Name, e.Author, e.Rating, e.Deviation, e.Games, e.Wins)
+	}
+	return tw.Flush()
+}
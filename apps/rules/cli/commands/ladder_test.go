@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"rules/ladder"
+
+	"github.com/stretchr/testify/require"
+)
+
+// buildTestLadder creates a ladder file with the named snakes registered, in order from strongest to weakest.
+func buildTestLadder(t *testing.T, algorithm string, names ...string) (*ladder.Ladder, string) {
+	path := filepath.Join(t.TempDir(), "ladder.json")
+	l, err := ladder.New(algorithm)
+	require.NoError(t, err)
+	for _, name := range names {
+		require.NoError(t, l.Register(name, "http://"+name+".example.com", ""))
+	}
+	require.NoError(t, l.Save(path))
+	return l, path
+}
+
+// strengthOrder plays games where snakes earlier in names always place above snakes later in names.
+func strengthOrder(names ...string) func([]ladder.Snake, int64, string) ([]Placement, error) {
+	strength := map[string]int{}
+	for i, name := range names {
+		strength[name] = i
+	}
+	return func(snakes []ladder.Snake, _ int64, _ string) ([]Placement, error) {
+		ranked := make([]string, 0, len(snakes))
+		for _, snake := range snakes {
+			ranked = append(ranked, snake.Name)
+		}
+		sort.Slice(ranked, func(i, j int) bool { return strength[ranked[i]] < strength[ranked[j]] })
+		placements := make([]Placement, 0, len(ranked))
+		for i, name := range ranked {
+			placements = append(placements, Placement{Place: i + 1, Name: name})
+		}
+		return placements, nil
+	}
+}
+
+func TestLadderRun(t *testing.T) {
+	for _, algorithm := range []string{ladder.AlgorithmElo, ladder.AlgorithmGlicko2} {
+		t.Run(algorithm, func(t *testing.T) {
+			l, path := buildTestLadder(t, algorithm, "a", "b", "c", "d")
+			leaderboardPath := filepath.Join(filepath.Dir(path), "leaderboard.json")
+			runner, err := newLadderRunner(l, GameState{Seed: 1}, ladderRunOptions{
+				LadderPath: path, Players: 3, Games: 40, Parallel: 4, LeaderboardPath: leaderboardPath,
+			})
+			require.NoError(t, err)
+			runner.playGame = strengthOrder("a", "b", "c", "d")
+
+			require.NoError(t, runner.run(context.Background()))
+			require.Equal(t, 40, l.Games)
+
+			entries := l.Leaderboard()
+			require.Equal(t, []string{"a", "b", "c", "d"}, []string{entries[0].Name, entries[1].Name, entries[2].Name, entries[3].Name})
+			games := 0
+			for _, entry := range entries {
+				games += entry.Games
+			}
+			require.Equal(t, 120, games)
+
+			saved, err := ladder.Load(path)
+			require.NoError(t, err)
+			require.Equal(t, 40, saved.Games)
+			require.Equal(t, entries, saved.Leaderboard())
+
+			contents, err := os.ReadFile(leaderboardPath)
+			require.NoError(t, err)
+			var written []ladder.Entry
+			require.NoError(t, json.Unmarshal(contents, &written))
+			require.Equal(t, entries, written)
+		})
+	}
+}
+
+func TestLadderRunNumbersGames(t *testing.T) {
+	l, path := buildTestLadder(t, ladder.AlgorithmElo, "a", "b")
+	l.Games = 10
+	outputDir := t.TempDir()
+	runner, err := newLadderRunner(l, GameState{Seed: 100}, ladderRunOptions{
+		LadderPath: path, Players: 2, Games: 3, Parallel: 1, OutputDir: outputDir,
+	})
+	require.NoError(t, err)
+
+	var mutex sync.Mutex
+	var seeds []int64
+	var logPaths []string
+	play := strengthOrder("a", "b")
+	runner.playGame = func(snakes []ladder.Snake, seed int64, logPath string) ([]Placement, error) {
+		mutex.Lock()
+		seeds = append(seeds, seed)
+		logPaths = append(logPaths, logPath)
+		mutex.Unlock()
+		return play(snakes, seed, logPath)
+	}
+
+	require.NoError(t, runner.run(context.Background()))
+	require.Equal(t, []int64{111, 112, 113}, seeds)
+	require.Equal(t, []string{
+		filepath.Join(outputDir, "games", "11.jsonl"),
+		filepath.Join(outputDir, "games", "12.jsonl"),
+		filepath.Join(outputDir, "games", "13.jsonl"),
+	}, logPaths)
+}
+
+func TestLadderRunStops(t *testing.T) {
+	l, path := buildTestLadder(t, ladder.AlgorithmGlicko2, "a", "b", "c")
+	runner, err := newLadderRunner(l, GameState{Seed: 1}, ladderRunOptions{LadderPath: path, Players: 2, Parallel: 2})
+	require.NoError(t, err)
+
+	// Games are played until the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	play := strengthOrder("a", "b", "c")
+	runner.playGame = func(snakes []ladder.Snake, seed int64, logPath string) ([]Placement, error) {
+		if seed >= 6 {
+			cancel()
+		}
+		return play(snakes, seed, logPath)
+	}
+	require.NoError(t, runner.run(ctx))
+	require.GreaterOrEqual(t, l.Games, 5)
+
+	// Errors stop the ladder
+	runner.playGame = func([]ladder.Snake, int64, string) ([]Placement, error) {
+		return nil, errors.New("snake exploded")
+	}
+	err = runner.run(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "snake exploded")
+}
+
+func TestNewLadderRunnerErrors(t *testing.T) {
+	l, path := buildTestLadder(t, ladder.AlgorithmElo, "a", "b")
+	tests := []struct {
+		name string
+		opts ladderRunOptions
+		err  string
+	}{
+		{"one player", ladderRunOptions{LadderPath: path, Players: 1, Parallel: 1}, "players must be at least 2, got 1"},
+		{"too few snakes", ladderRunOptions{LadderPath: path, Players: 3, Parallel: 1}, "the ladder needs at least 3 snakes, 2 are registered"},
+		{"negative games", ladderRunOptions{LadderPath: path, Players: 2, Games: -1, Parallel: 1}, "games must not be negative, got -1"},
+		{"parallel", ladderRunOptions{LadderPath: path, Players: 2}, "parallel must be at least 1, got 0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newLadderRunner(l, GameState{}, test.opts)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestLadderRunsGames(t *testing.T) {
+	l, path := buildTestLadder(t, ladder.AlgorithmGlicko2, "a", "b", "c")
+	outputDir := t.TempDir()
+	template := *buildDefaultGameState()
+	template.MaxTurns = 3
+	runner, err := newLadderRunner(l, template, ladderRunOptions{
+		LadderPath: path, Players: 3, Games: 2, Parallel: 2, OutputDir: outputDir,
+	})
+	require.NoError(t, err)
+	runner.httpClient = stubHTTPClient{nil, 200, func(_ string) string { return `{"move": "down"}` }, time.Millisecond}
+
+	require.NoError(t, runner.run(context.Background()))
+	require.Equal(t, 2, l.Games)
+	for _, snake := range l.Snakes {
+		require.Equal(t, 2, snake.Games)
+	}
+	for _, name := range []string{"1.jsonl", "2.jsonl"} {
+		contents, err := os.ReadFile(filepath.Join(outputDir, "games", name))
+		require.NoError(t, err)
+		require.Contains(t, string(contents), `"placements":[`)
+	}
+}
+
+func TestLadderCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ladder.json")
+	runLadder := func(args ...string) (string, error) {
+		cmd := NewLadderCommand()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+		cmd.SetArgs(append(args, "--file", path))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	_, err := runLadder("register", "--name", "a")
+	require.EqualError(t, err, "a name and URL, or a participants file, must be given")
+
+	_, err = runLadder("register", "--name", "a", "--url", "http://a.example.com", "--author", "ann", "--algorithm", "elo")
+	require.NoError(t, err)
+	_, err = runLadder("register", "--name", "b", "--url", "http://b.example.com")
+	require.NoError(t, err)
+
+	l, err := ladder.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, ladder.AlgorithmElo, l.Algorithm)
+	require.Len(t, l.Snakes, 2)
+	require.NoError(t, l.Record([]ladder.Result{{Name: "b", Place: 1}, {Name: "a", Place: 2}}))
+	require.NoError(t, l.Save(path))
+
+	out, err := runLadder("leaderboard")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, []string{"RANK", "NAME", "AUTHOR", "RATING", "DEVIATION", "GAMES", "WINS"}, strings.Fields(lines[0]))
+	require.Equal(t, []string{"1", "b", "1516", "0", "1", "1"}, strings.Fields(lines[1]))
+	require.Equal(t, []string{"2", "a", "ann", "1484", "0", "1", "0"}, strings.Fields(lines[2]))
+
+	out, err = runLadder("leaderboard", "--json")
+	require.NoError(t, err)
+	var entries []ladder.Entry
+	require.NoError(t, json.Unmarshal([]byte(out), &entries))
+	require.Equal(t, l.Leaderboard(), entries)
+}
@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"rules"
+	"rules/maps"
+	"rules/settings"
+
+	"github.com/spf13/cobra"
+)
+
+type mapsPreviewOptions struct {
+	Width   int
+	Height  int
+	Players int
+	Seed    int64
+	MapFile string
+}
+
+func NewMapsCommand() *cobra.Command {
+	var mapsCmd = &cobra.Command{
+		Use:   "maps",
+		Short: "List, describe and preview game maps.",
+		Long:  "List, describe and preview the game maps that can be used with the play command.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listMaps(cmd.OutOrStdout())
+		},
+	}
+
+	var listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List all built-in maps.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listMaps(cmd.OutOrStdout())
+		},
+	}
+
+	var infoMapFile string
+	var infoCmd = &cobra.Command{
+		Use:   "info [map]",
+		Short: "Show the metadata for a map.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gameMap, err := loadMapFromArgs(args, infoMapFile)
+			if err != nil {
+				return err
+			}
+			describeMap(cmd.OutOrStdout(), gameMap)
+			return nil
+		},
+	}
+	infoCmd.Flags().StringVar(&infoMapFile, "map-file", "", "Path to a YAML or JSON map file to describe instead of a built-in map")
+
+	opts := mapsPreviewOptions{}
+	var previewCmd = &cobra.Command{
+		Use:   "preview [map]",
+		Short: "Render the starting board for a map.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gameMap, err := loadMapFromArgs(args, opts.MapFile)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("seed") {
+				opts.Seed = time.Now().UTC().UnixNano()
+			}
+			return previewMap(cmd.OutOrStdout(), gameMap, opts)
+		},
+	}
+	previewCmd.Flags().IntVarP(&opts.Width, "width", "W", 0, "Width of Board (defaults to the map's size if it only supports one, otherwise 11)")
+	previewCmd.Flags().IntVarP(&opts.Height, "height", "H", 0, "Height of Board (defaults to the map's size if it only supports one, otherwise 11)")
+	previewCmd.Flags().IntVarP(&opts.Players, "players", "p", 2, "Number of snakes to place on the board")
+	previewCmd.Flags().Int64Var(&opts.Seed, "seed", 0, "Random seed used to set up the board (defaults to the current time)")
+	previewCmd.Flags().StringVar(&opts.MapFile, "map-file", "", "Path to a YAML or JSON map file to preview instead of a built-in map")
+
+	mapsCmd.AddCommand(listCmd, infoCmd, previewCmd)
+
+	return mapsCmd
+}
+
+func loadMapFromArgs(args []string, mapFile string) (maps.GameMap, error) {
+	if mapFile != "" {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("can't use both a map name and --map-file")
+		}
+		return maps.LoadMapFile(mapFile)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("a map name or --map-file is required")
+	}
+	gameMap, err := maps.GetMap(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("unknown map %q: %w", args[0], err)
+	}
+	return gameMap, nil
+}
+
+func listMaps(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tPLAYERS\tBOARD SIZES")
+	for _, id := range maps.List() {
+		gameMap, err := maps.GetMap(id)
+		if err != nil {
+			return err
+		}
+		meta := gameMap.Meta()
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", id, meta.Name, formatPlayers(meta), formatBoardSizes(meta))
+	}
+	return tw.Flush()
+}
+
+func describeMap(w io.Writer, gameMap maps.GameMap) {
+	meta := gameMap.Meta()
+	fmt.Fprintf(w, "ID:          %s\n", gameMap.ID())
+	fmt.Fprintf(w, "Name:        %s\n", meta.Name)
+	fmt.Fprintf(w, "Players:     %s\n", formatPlayers(meta))
+	fmt.Fprintf(w, "Board sizes: %s\n", formatBoardSizes(meta))
+}
+
+func previewMap(w io.Writer, gameMap maps.GameMap, opts mapsPreviewOptions) error {
+	width, height := opts.Width, opts.Height
+	if sizes := gameMap.Meta().BoardSizes; len(sizes) == 1 && !sizes.IsUnlimited() {
+		if width == 0 {
+			width = sizes[0].Width
+		}
+		if height == 0 {
+			height = sizes[0].Height
+		}
+	}
+	if width == 0 {
+		width = rules.BoardSizeMedium
+	}
+	if height == 0 {
+		height = rules.BoardSizeMedium
+	}
+
+	snakeIDs := make([]string, 0, opts.Players)
+	for i := 0; i < opts.Players; i++ {
+		snakeIDs = append(snakeIDs, fmt.Sprint(i))
+	}
+
+	s := settings.NewSettings(map[string]string{}).WithSeed(opts.Seed)
+	boardState, err := maps.SetupBoard(gameMap, s, width, height, snakeIDs)
+	if err != nil {
+		return fmt.Errorf("unable to set up %dx%d board for %d players: %w", width, height, opts.Players, err)
+	}
+	if err := gameMap.Meta().Validate(boardState); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%s (%s) %dx%d, %d players, seed %d\n", gameMap.Meta().Name, gameMap.ID(), width, height, opts.Players, opts.Seed)
+	fmt.Fprint(w, renderMapPreview(boardState))
+	fmt.Fprintln(w, "Legend: A-Z snake start, * food, # hazard, . empty")
+	return nil
+}
+
+// renderMapPreview draws the board top row first, labelling snakes A, B, C...
+func renderMapPreview(boardState *rules.BoardState) string {
+	grid := make([][]rune, boardState.Height)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(".", boardState.Width))
+	}
+	set := func(p rules.Point, c rune) {
+		if p.X >= 0 && p.X < boardState.Width && p.Y >= 0 && p.Y < boardState.Height {
+			grid[p.Y][p.X] = c
+		}
+	}
+
+	for _, p := range boardState.Hazards {
+		set(p, '#')
+	}
+	for _, p := range boardState.Food {
+		set(p, '*')
+	}
+	for i, snake := range boardState.Snakes {
+		for _, p := range snake.Body {
+			set(p, rune('A'+i%26))
+		}
+	}
+
+	var sb strings.Builder
+	for y := boardState.Height - 1; y >= 0; y-- {
+		sb.WriteString(string(grid[y]))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func formatPlayers(meta maps.Metadata) string {
+	if meta.MaxPlayers == 0 {
+		return fmt.Sprintf("%d+", meta.MinPlayers)
+	}
+	return fmt.Sprintf("%d-%d", meta.MinPlayers, meta.MaxPlayers)
+}
+
+func formatBoardSizes(meta maps.Metadata) string {
+	if len(meta.BoardSizes) == 0 || meta.BoardSizes.IsUnlimited() {
+		return "any"
+	}
+	sizeStrings := make([]string, 0, len(meta.BoardSizes))
+	for _, size := range meta.BoardSizes {
+		sizeStrings = append(sizeStrings, fmt.Sprintf("%dx%d", size.Width, size.Height))
+	}
+	return strings.Join(sizeStrings, ", ")
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"rules"
+	"rules/maps"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMapMetadata(t *testing.T) {
+	tests := []struct {
+		meta    maps.Metadata
+		players string
+		sizes   string
+	}{
+		{maps.Metadata{MinPlayers: 1, MaxPlayers: 8, BoardSizes: maps.AnySize()}, "1-8", "any"},
+		{maps.Metadata{MinPlayers: 2, BoardSizes: maps.OddSizes(7, 11)}, "2+", "7x7, 9x9, 11x11"},
+		{maps.Metadata{MinPlayers: 1, MaxPlayers: 6, BoardSizes: maps.FixedSizes(maps.Dimensions{Width: 19, Height: 21})}, "1-6", "19x21"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.players, formatPlayers(test.meta))
+		require.Equal(t, test.sizes, formatBoardSizes(test.meta))
+	}
+}
+
+func TestListMaps(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, listMaps(&out))
+
+	for _, id := range maps.List() {
+		require.Contains(t, out.String(), id)
+	}
+	require.Contains(t, out.String(), "Arcade Maze")
+	require.Contains(t, out.String(), "19x21")
+}
+
+func TestRenderMapPreview(t *testing.T) {
+	boardState := rules.NewBoardState(4, 3).
+		WithFood([]rules.Point{{X: 3, Y: 2}}).
+		WithHazards([]rules.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}).
+		WithSnakes([]rules.Snake{
+			{ID: "0", Body: []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 1}}},
+			{ID: "1", Body: []rules.Point{{X: 3, Y: 0}}},
+		})
+
+	require.Equal(t, "...*\n.A..\n##.B\n", renderMapPreview(boardState))
+}
+
+func TestMapsCommand(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		cmd := NewMapsCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("info", "arcade_maze")
+	require.NoError(t, err)
+	require.Contains(t, out, "Players:     1-6")
+
+	out, err = run("preview", "arcade_maze", "--seed", "1", "--players", "4")
+	require.NoError(t, err)
+	require.Contains(t, out, "19x21, 4 players, seed 1")
+	require.Contains(t, out, "###################\n#*.......#.......*#\n")
+
+	out, err = run("preview", "--map-file", "../../maps/testdata/arena.yaml", "--seed", "1")
+	require.NoError(t, err)
+	require.Contains(t, out, "Arena (arena) 7x7, 2 players")
+
+	_, err = run("preview", "arcade_maze", "--width", "11", "--height", "11")
+	require.Error(t, err)
+
+	_, err = run("info", "missing")
+	require.ErrorIs(t, err, rules.ErrorMapNotFound)
+
+	_, err = run("preview")
+	require.Error(t, err)
+}
@@ -2,13 +2,18 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -18,8 +23,10 @@ import (
 	"rules/client"
 	"rules/maps"
 	"rules/rulesets"
+	"rules/settings"
 
 	"github.com/google/uuid"
+	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 	log "github.com/spf13/jwalterweatherman"
 )
@@ -29,6 +36,7 @@ type SnakeState struct {
 	URL        string
 	Name       string
 	ID         string
+	Squad      string
 	LastMove   string
 	Character  rune
 	Color      string
@@ -38,20 +46,43 @@ type SnakeState struct {
 	Error      error
 	StatusCode int
 	Latency    time.Duration
+	// InvalidMove is set when the snake responded to the last move request without a valid move
+	InvalidMove bool
+	Timeout     int
+	TimeBank    time.Duration
+	Overrun     time.Duration
 }
 
 type GameState struct {
-	Width           int
-	Height          int
-	Names           []string
-	URLs            []string
-	Timeout         int
-	GameType        string
-	Seed            int64
-	ViewInBrowser   bool
-	BoardURL        string
-	Debug           bool
-	FoodSpawnChance int
+	Width               int
+	Height              int
+	Names               []string
+	URLs                []string
+	Squads              []string
+	Timeout             int
+	SnakeTimeouts       []int
+	TimeBank            int
+	GameType            string
+	MapName             string
+	MapFile             string
+	Seed                int64
+	ViewInBrowser       bool
+	BoardURL            string
+	BoardAddr           string
+	Debug               bool
+	FoodSpawnChance     int
+	MinimumFood         int
+	HazardDamagePerTurn int
+	ShrinkEveryNTurns   int
+	AllowBodyCollisions bool
+	SharedElimination   bool
+	SharedHealth        bool
+	SharedLength        bool
+	MaxTurns            int
+	TieBreak            string
+	OutputPath          string
+	Render              string
+	RenderColor         bool
 
 	// Internal game state
 	settings    map[string]string
@@ -59,6 +90,14 @@ type GameState struct {
 	httpClient  TimedHttpClient
 	ruleset     rulesets.Ruleset
 	gameMap     maps.GameMap
+	gameResult  *result // set once Run has finished the game
+
+	// Set when the board size was given explicitly, so that a map file's size can't silently replace it
+	sizeChanged bool
+
+	// Set when the game is hosted by the serve command, which streams it from its own board server and can cancel it
+	sendEvent func(board.GameEvent)
+	ctx       context.Context
 }
 
 func NewPlayCommand() *cobra.Command {
@@ -69,6 +108,7 @@ func NewPlayCommand() *cobra.Command {
 		Short: "Play a game of Battlesnake locally.",
 		Long:  "Play a game of Battlesnake locally.",
 		Run: func(cmd *cobra.Command, args []string) {
+			gameState.sizeChanged = boardSizeChanged(cmd)
 			if err := gameState.Initialize(); err != nil {
 				log.ERROR.Fatalf("Error initializing game: %v", err)
 			}
@@ -78,40 +118,124 @@ func NewPlayCommand() *cobra.Command {
 		},
 	}
 
-	playCmd.Flags().IntVarP(&gameState.Width, "width", "W", 11, "Width of Board")
-	playCmd.Flags().IntVarP(&gameState.Height, "height", "H", 11, "Height of Board")
-	playCmd.Flags().StringArrayVarP(&gameState.Names, "name", "n", nil, "Name of Snake")
-	playCmd.Flags().StringArrayVarP(&gameState.URLs, "url", "u", nil, "URL of Snake")
-
-	playCmd.Flags().StringVarP(&gameState.GameType, "gametype", "g", "standard", "Type of Game Rules")
+	addSnakeFlags(playCmd, gameState)
+	addGameFlags(playCmd, gameState)
 	playCmd.Flags().BoolVar(&gameState.ViewInBrowser, "browser", true, "View the game in the browser using the Battlesnake game board")
-	playCmd.Flags().StringVar(&gameState.BoardURL, "board-url", "https://board.battlesnake.com", "Base URL for the game board when using --browser")
+	playCmd.Flags().StringVar(&gameState.BoardURL, "board-url", "", "Base URL of a hosted game board to use with --browser instead of the one built in")
 	playCmd.Flags().BoolVar(&gameState.Debug, "debug", false, "Log Board State")
-
-	playCmd.Flags().IntVar(&gameState.FoodSpawnChance, "foodSpawnChance", 10, "Percentage chance of spawning a new food every round")
+	playCmd.Flags().StringVarP(&gameState.OutputPath, "output", "o", "", "File path to write the game log to as JSON lines, or - for stdout")
+	playCmd.Flags().StringVar(&gameState.Render, "render", RenderNone, "Draw the board in the terminal every turn, only \"ascii\" is supported")
+	playCmd.Flags().BoolVar(&gameState.RenderColor, "color", false, "Use the snakes' colours when drawing the board with --render")
 
 	playCmd.Flags().SortFlags = false
 
 	return playCmd
 }
 
+// addSnakeFlags adds the flags used to choose the snakes in a game.
+func addSnakeFlags(cmd *cobra.Command, gameState *GameState) {
+	cmd.Flags().StringArrayVarP(&gameState.Names, "name", "n", nil, "Name of Snake")
+	cmd.Flags().StringArrayVarP(&gameState.URLs, "url", "u", nil, "URL of Snake")
+	cmd.Flags().StringArrayVarP(&gameState.Squads, "squad", "s", nil, "Squad of Snake")
+	cmd.Flags().IntSliceVar(&gameState.SnakeTimeouts, "snake-timeout", nil, "Request Timeout of Snake, overrides --timeout (0 to use --timeout)")
+}
+
+// boardSizeChanged reports whether --width or --height was given on the command line.
+func boardSizeChanged(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("width") || cmd.Flags().Changed("height")
+}
+
+// addGameFlags adds the flags used to set up a game, which are shared by the commands that run games.
+func addGameFlags(cmd *cobra.Command, gameState *GameState) {
+	cmd.Flags().IntVarP(&gameState.Width, "width", "W", 11, "Width of Board")
+	cmd.Flags().IntVarP(&gameState.Height, "height", "H", 11, "Height of Board")
+	cmd.Flags().IntVarP(&gameState.Timeout, "timeout", "t", 500, "Request Timeout")
+	cmd.Flags().IntVar(&gameState.TimeBank, "time-bank", 0, "Milliseconds each snake may spend over its timeout across the whole game (0 to disable)")
+
+	cmd.Flags().StringVarP(&gameState.GameType, "gametype", "g", "standard", "Type of Game Rules")
+	cmd.Flags().StringVarP(&gameState.MapName, "map", "m", "standard", "Game map to use to populate the board")
+	cmd.Flags().StringVar(&gameState.MapFile, "map-file", "", "Path to a YAML or JSON map file to use instead of --map")
+	cmd.Flags().Int64VarP(&gameState.Seed, "seed", "r", 0, "Random seed, use the same seed and snake moves to replay a game (defaults to the current time)")
+
+	cmd.Flags().IntVar(&gameState.FoodSpawnChance, "foodSpawnChance", 10, "Percentage chance of spawning a new food every round")
+	cmd.Flags().IntVar(&gameState.MinimumFood, "minimumFood", 1, "Minimum food to keep on the board every turn")
+	cmd.Flags().IntVar(&gameState.HazardDamagePerTurn, "hazardDamagePerTurn", 14, "Health damage a snake will take when ending its turn in a hazard")
+	cmd.Flags().IntVar(&gameState.ShrinkEveryNTurns, "shrinkEveryNTurns", 25, "In Royale mode, the number of turns between generating new hazards (shrinking the safe board space)")
+	cmd.Flags().BoolVar(&gameState.AllowBodyCollisions, "allowBodyCollisions", true, "In Squad mode, allow snakes on the same squad to move through each other's bodies")
+	cmd.Flags().BoolVar(&gameState.SharedElimination, "sharedElimination", true, "In Squad mode, eliminate every snake on a squad when one of them is eliminated")
+	cmd.Flags().BoolVar(&gameState.SharedHealth, "sharedHealth", false, "In Squad mode, keep the health of snakes on the same squad in sync")
+	cmd.Flags().BoolVar(&gameState.SharedLength, "sharedLength", false, "In Squad mode, keep the length of snakes on the same squad in sync")
+	cmd.Flags().IntVar(&gameState.MaxTurns, "maxTurns", 0, "Maximum number of turns before the game ends (0 for no limit)")
+	cmd.Flags().StringVar(&gameState.TieBreak, "tieBreak", rulesets.DefaultTieBreak, "Comma separated criteria used to rank the remaining snakes when maxTurns is reached (length, health, kills)")
+}
+
 // Setup a GameState once all the fields have been parsed from the command-line.
 func (gameState *GameState) Initialize() error {
-	gameState.Seed = time.Now().UTC().UnixNano()
+	if gameState.Seed == 0 {
+		gameState.Seed = time.Now().UTC().UnixNano()
+	}
+
+	if gameState.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0, got %d", gameState.Timeout)
+	}
+	for _, timeout := range gameState.SnakeTimeouts {
+		if timeout < 0 {
+			return fmt.Errorf("snake timeout can't be negative, got %d", timeout)
+		}
+	}
+	if gameState.TimeBank < 0 {
+		return fmt.Errorf("time bank can't be negative, got %d", gameState.TimeBank)
+	}
+	if gameState.Render != RenderNone && gameState.Render != RenderASCII {
+		return fmt.Errorf("unknown renderer %q, only %q is supported", gameState.Render, RenderASCII)
+	}
+	if len(gameState.Squads) > 0 && gameState.GameType != rules.GameTypeSquad {
+		return fmt.Errorf("squads can only be used with the %q game type, got %q", rules.GameTypeSquad, gameState.GameType)
+	}
 
 	// Set up HTTP client with request timeout
-	gameState.Timeout = 500
 	gameState.httpClient = timedHTTPClient{
 		&http.Client{
 			Timeout: time.Duration(gameState.Timeout) * time.Millisecond,
 		},
 	}
 
-	gameState.gameMap = maps.StandardMap{}
+	if gameState.MapFile != "" {
+		gameMap, err := maps.LoadMapFile(gameState.MapFile)
+		if err != nil {
+			return fmt.Errorf("error loading map file: %w", err)
+		}
+		// Map files describe a single fixed board size
+		boardSize := gameMap.Meta().BoardSizes[0]
+		if gameState.sizeChanged && (gameState.Width != boardSize.Width || gameState.Height != boardSize.Height) {
+			return fmt.Errorf("board size %dx%d doesn't match the %dx%d size of map file %s", gameState.Width, gameState.Height, boardSize.Width, boardSize.Height, gameState.MapFile)
+		}
+		gameState.Width, gameState.Height = boardSize.Width, boardSize.Height
+		gameState.gameMap = gameMap
+	} else {
+		gameMap, err := maps.GetMap(gameState.MapName)
+		if err != nil {
+			return fmt.Errorf("unknown map %q: %w", gameState.MapName, err)
+		}
+		gameState.gameMap = gameMap
+	}
 
 	// Create settings object
 	gameState.settings = map[string]string{
-		rules.ParamFoodSpawnChance: fmt.Sprint(gameState.FoodSpawnChance),
+		rules.ParamGameType:            gameState.GameType,
+		rules.ParamFoodSpawnChance:     fmt.Sprint(gameState.FoodSpawnChance),
+		rules.ParamMinimumFood:         fmt.Sprint(gameState.MinimumFood),
+		rules.ParamHazardDamagePerTurn: fmt.Sprint(gameState.HazardDamagePerTurn),
+		rules.ParamShrinkEveryNTurns:   fmt.Sprint(gameState.ShrinkEveryNTurns),
+		rules.ParamAllowBodyCollisions: fmt.Sprint(gameState.AllowBodyCollisions),
+		rules.ParamSharedElimination:   fmt.Sprint(gameState.SharedElimination),
+		rules.ParamSharedHealth:        fmt.Sprint(gameState.SharedHealth),
+		rules.ParamSharedLength:        fmt.Sprint(gameState.SharedLength),
+		rules.ParamMaxTurns:            fmt.Sprint(gameState.MaxTurns),
+		rules.ParamTieBreak:            gameState.TieBreak,
+	}
+	if err := settings.NewSettings(gameState.settings).Validate(); err != nil {
+		return err
 	}
 
 	// Build ruleset from settings
@@ -128,6 +252,86 @@ func (gameState *GameState) Initialize() error {
 	return nil
 }
 
+// runHeadlessGame plays a game between the given snakes with the settings of a template game, without rendering
+// it or opening the board, and returns its result. It is used to play many games for tournaments and the ladder.
+func runHeadlessGame(template GameState, names, urls []string, seed int64, logPath string, httpClient TimedHttpClient) (*result, error) {
+	gameState := template
+	gameState.Names = names
+	gameState.URLs = urls
+	gameState.Seed = seed
+	gameState.OutputPath = logPath
+	gameState.ViewInBrowser = false
+	gameState.Render = RenderNone
+
+	if logPath != "" {
+		if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+			return nil, err
+		}
+	}
+	if err := gameState.Initialize(); err != nil {
+		return nil, err
+	}
+	if httpClient != nil {
+		gameState.httpClient = httpClient
+	}
+	if err := gameState.Run(); err != nil {
+		return nil, err
+	}
+	return gameState.gameResult, nil
+}
+
+// runParallel plays the games handed out by next, up to parallel of them at a time, until next returns a nil game.
+// Once a game fails or ctx is cancelled no more games are started. The games already being played are passed a
+// context that is cancelled on the first failure, and are waited for before the first error is returned.
+func runParallel(ctx context.Context, parallel int, next func() (func(context.Context) error, error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errMutex sync.Mutex
+	var firstErr error
+	fail := func(err error) {
+		errMutex.Lock()
+		defer errMutex.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		cancel()
+	}
+
+	semaphore := make(chan struct{}, parallel)
+	for {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		game, err := next()
+		if err != nil {
+			fail(err)
+			break
+		}
+		if game == nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if err := game(ctx); err != nil {
+				fail(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
 // Setup and run a full game.
 func (gameState *GameState) Run() error {
 	var gameOver bool
@@ -135,14 +339,14 @@ func (gameState *GameState) Run() error {
 	var winner SnakeState
 	var isDraw = false
 
+	log.INFO.Printf("Starting game with seed %d", gameState.Seed)
+
 	// Setup local state for snakes
 	gameState.snakeStates, err = gameState.buildSnakesFromOptions()
 	if err != nil {
 		return fmt.Errorf("error getting snake metadata: %w", err)
 	}
 
-	rand.Seed(gameState.Seed)
-
 	gameOver, boardState, err := gameState.initializeBoardFromArgs()
 	if err != nil {
 		return fmt.Errorf("error initializing board: %w", err)
@@ -153,125 +357,250 @@ func (gameState *GameState) Run() error {
 		snakeRequests: make([]client.SnakeRequest, 0),
 		winner:        SnakeState{},
 		isDraw:        false,
+		seed:          gameState.Seed,
 	}
 
-	boardGame := board.Game{
-		Status: "running",
-		Width:  gameState.Width,
-		Height: gameState.Height,
-		Ruleset: map[string]string{
-			rules.ParamGameType: gameState.GameType,
-		},
-		RulesetName: gameState.GameType,
-		RulesStages: []string{},
-		Map:         "standard",
+	var outputFile io.Writer
+	// The board is drawn on stdout unless the game log is being written there
+	renderOutput := io.Writer(os.Stdout)
+	if gameState.OutputPath == "-" {
+		outputFile = os.Stdout
+		renderOutput = os.Stderr
+	} else if gameState.OutputPath != "" {
+		f, err := os.Create(gameState.OutputPath)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %w", err)
+		}
+		defer f.Close()
+		outputFile = f
 	}
 
-	boardServer := board.NewBoardServer(boardGame)
-
-	if gameState.ViewInBrowser {
+	boardGame := gameState.buildBoardGame()
+	sendEvent := gameState.sendEvent
+	if gameState.ViewInBrowser && sendEvent == nil {
+		boardServer := board.NewBoardServer(gameState.BoardAddr)
+		boardGameID := uuid.New().String()
+		if err := boardServer.AddGame(boardGameID, boardGame); err != nil {
+			return err
+		}
 		serverURL, err := boardServer.Listen()
 		if err != nil {
 			return fmt.Errorf("error starting HTTP server: %w", err)
 		}
 		defer boardServer.Shutdown()
 		log.INFO.Printf("Board server listening on %s", serverURL)
+		openBoard(gameState.BoardURL, serverURL, boardGameID)
 
-		// boardURL := fmt.Sprintf(gameState.BoardURL+"?engine=%s&game=%s&autoplay=true", serverURL, uuid.New().String())
-		// if err := browser.OpenURL(boardURL); err != nil {
-		// 	log.ERROR.Printf("Failed to open browser: %v", err)
-		// }
-
-		for !boardServer.IsConnected() {
+		for !boardServer.IsConnected(boardGameID) {
 			time.Sleep(100 * time.Millisecond)
 		}
+		sendEvent = func(event board.GameEvent) {
+			boardServer.SendEvent(boardGameID, event)
+		}
+	}
 
+	if sendEvent != nil {
 		// send turn zero to websocket server
-		boardServer.SendEvent(gameState.buildFrameEvent(boardState))
+		sendEvent(gameState.buildFrameEvent(boardState))
 	}
 
-	// gameState.printState(boardState)
+	if gameState.Render == RenderASCII {
+		gameState.renderBoard(renderOutput, boardState)
+	}
 
-	for _, snakeState := range gameState.snakeStates {
-		snakeRequest := gameState.getRequestBodyForSnake(boardState, snakeState)
-		gameExporter.AddSnakeRequest(snakeRequest)
-		break
+	if err := gameState.exportTurn(&gameExporter, outputFile, boardState); err != nil {
+		return err
 	}
 
+	stats := newGameStats(boardState)
+
 	for !gameOver {
+		if gameState.ctx != nil && gameState.ctx.Err() != nil {
+			return fmt.Errorf("game stopped on turn %d: %w", boardState.Turn, gameState.ctx.Err())
+		}
+		prevBoardState := boardState
 		gameOver, boardState, err = gameState.createNextBoardState(boardState)
 		if err != nil {
 			return fmt.Errorf("error processing game: %w", err)
 		}
+		stats.recordTurn(prevBoardState, boardState, gameState.snakeStates)
 
 		if gameOver {
 			break
 		}
 
-		// gameState.printState(boardState)
-
-		if gameState.ViewInBrowser {
-			boardServer.SendEvent(gameState.buildFrameEvent(boardState))
+		if gameState.Render == RenderASCII {
+			gameState.renderBoard(renderOutput, boardState)
 		}
 
-		for _, snakeState := range gameState.snakeStates {
-			snakeRequest := gameState.getRequestBodyForSnake(boardState, snakeState)
-			gameExporter.AddSnakeRequest(snakeRequest)
-			json, _ := gameExporter.ConvertToJSON(true)
-			fmt.Println(json[len(json)-1])
+		if sendEvent != nil {
+			sendEvent(gameState.buildFrameEvent(boardState))
+		}
 
-			break
+		if err := gameState.exportTurn(&gameExporter, outputFile, boardState); err != nil {
+			return err
 		}
 	}
 
-	if len(gameState.snakeStates) > 1 {
-		// A draw is possible if there is more than one snake in the game.
-		isDraw = true
-	}
+	winner, isDraw = gameState.determineWinner(boardState)
 
 	for _, snake := range boardState.Snakes {
-		snakeState := gameState.snakeStates[snake.ID]
-		if snake.EliminatedCause == rules.NotEliminated {
-			isDraw = false
-			winner = snakeState
-		}
+		gameState.sendEndRequest(boardState, gameState.snakeStates[snake.ID])
+	}
 
-		gameState.sendEndRequest(boardState, snakeState)
+	stats.finish(boardState)
+	gameExporter.Finish(winner, isDraw, gameState.buildPlacements(boardState, stats))
+	gameResult := gameExporter.result()
+	gameState.gameResult = &gameResult
+	if outputFile != nil {
+		if _, err := gameExporter.FlushToFile(outputFile); err != nil {
+			return fmt.Errorf("error writing game log: %w", err)
+		}
 	}
 
 	if isDraw {
-		log.INFO.Printf("Game completed after %v turns. It was a draw.", boardState.Turn)
+		log.INFO.Printf("Game completed after %v turns with seed %d. It was a draw.", boardState.Turn, gameState.Seed)
+	} else if winner.Squad != "" {
+		log.INFO.Printf("Game completed after %v turns with seed %d. Squad %v was the winner.", boardState.Turn, gameState.Seed, winner.Squad)
 	} else if winner.Name != "" {
-		log.INFO.Printf("Game completed after %v turns. %v was the winner.", boardState.Turn, winner.Name)
+		log.INFO.Printf("Game completed after %v turns with seed %d. %v was the winner.", boardState.Turn, gameState.Seed, winner.Name)
 	} else {
-		log.INFO.Printf("Game completed after %v turns.", boardState.Turn)
+		log.INFO.Printf("Game completed after %v turns with seed %d.", boardState.Turn, gameState.Seed)
 	}
 
-	if gameState.ViewInBrowser {
-		boardServer.SendEvent(board.GameEvent{
+	if sendEvent != nil {
+		sendEvent(board.GameEvent{
 			EventType: board.EVENT_TYPE_GAME_END,
 			Data:      boardGame,
 		})
 	}
 
-	jsonStr, err := json.Marshal(board.GameEvent{
-		EventType: board.EVENT_TYPE_GAME_END,
-		Data:      boardGame,
-	})
-	fmt.Println(jsonStr)
+	return nil
+}
+
+// buildBoardGame describes the game for the board viewer.
+func (gameState *GameState) buildBoardGame() board.Game {
+	return board.Game{
+		Status: "running",
+		Width:  gameState.Width,
+		Height: gameState.Height,
+		Ruleset: map[string]string{
+			rules.ParamGameType: gameState.GameType,
+		},
+		RulesetName:  gameState.GameType,
+		RulesStages:  []string{},
+		Map:          gameState.gameMap.ID(),
+		SnakeTimeout: gameState.Timeout,
+	}
+}
+
+// openBoard opens a game in the browser, using the board viewer built into the board server unless boardURL is given.
+func openBoard(boardURL string, serverURL string, gameID string) {
+	viewerURL := board.ViewerURL(serverURL, gameID)
+	if boardURL != "" {
+		viewerURL = fmt.Sprintf("%s?engine=%s&game=%s&autoplay=true", boardURL, url.QueryEscape(serverURL), url.QueryEscape(gameID))
+	}
+	log.INFO.Printf("Watch the game at %s", viewerURL)
+	if err := browser.OpenURL(viewerURL); err != nil {
+		log.ERROR.Printf("Failed to open browser: %v", err)
+	}
+}
 
+// exportTurn adds the request for the first snake on the board to the game log,
+// and writes it straight away so that the log is usable even if the game doesn't finish.
+func (gameState *GameState) exportTurn(gameExporter *GameExporter, outputFile io.Writer, boardState *rules.BoardState) error {
+	if len(boardState.Snakes) > 0 {
+		snakeState := gameState.snakeStates[boardState.Snakes[0].ID]
+		gameExporter.AddSnakeRequest(gameState.getRequestBodyForSnake(boardState, snakeState))
+	}
+	if outputFile == nil {
+		return nil
+	}
+	if _, err := gameExporter.FlushToFile(outputFile); err != nil {
+		return fmt.Errorf("error writing game log: %w", err)
+	}
 	return nil
 }
 
+// buildPlacements ranks every snake in the game, with the stats collected while it was played.
+func (gameState *GameState) buildPlacements(boardState *rules.BoardState, stats gameStats) []Placement {
+	snakes := map[string]rules.Snake{}
+	for _, snake := range boardState.Snakes {
+		snakes[snake.ID] = snake
+	}
+
+	placements := []Placement{}
+	place := 1
+	for _, rank := range rulesets.RankSnakes(boardState, gameState.ruleset.Settings()) {
+		for _, id := range rank {
+			snake := snakes[id]
+			placement := Placement{
+				Place:            place,
+				ID:               id,
+				Name:             gameState.snakeStates[id].Name,
+				Squad:            snake.Squad,
+				EliminatedCause:  snake.EliminatedCause,
+				EliminatedBy:     snake.EliminatedBy,
+				EliminatedOnTurn: snake.EliminatedOnTurn,
+			}
+			if s, ok := stats[id]; ok {
+				placement.Stats = *s
+				placement.Moves = s.moves
+			}
+			placements = append(placements, placement)
+		}
+		// Tied snakes share a place, and the places they took up are skipped
+		place += len(rank)
+	}
+	return placements
+}
+
+// determineWinner picks the winning snake from the final board state.
+// If the turn limit ended the game with several snakes remaining, they are ranked using the tie-break settings.
+func (gameState *GameState) determineWinner(boardState *rules.BoardState) (SnakeState, bool) {
+	ranks := rulesets.RankSurvivors(boardState, gameState.ruleset.Settings())
+	if gameState.MaxTurns > 0 && boardState.Turn >= gameState.MaxTurns && (len(ranks) > 1 || (len(ranks) == 1 && len(ranks[0]) > 1)) {
+		rankNames := make([]string, 0, len(ranks))
+		for _, rank := range ranks {
+			names := make([]string, 0, len(rank))
+			for _, id := range rank {
+				names = append(names, gameState.snakeStates[id].Name)
+			}
+			rankNames = append(rankNames, strings.Join(names, " = "))
+		}
+		log.INFO.Printf("Turn limit of %d reached, ranked by %s: %s", gameState.MaxTurns, gameState.TieBreak, strings.Join(rankNames, ", "))
+	}
+
+	if len(ranks) == 0 {
+		// A draw is possible if there is more than one snake in the game.
+		return SnakeState{}, len(gameState.snakeStates) > 1
+	}
+	if len(ranks[0]) > 1 {
+		// Squads win together, so tied survivors from the same squad aren't a draw
+		squad := gameState.snakeStates[ranks[0][0]].Squad
+		for _, id := range ranks[0][1:] {
+			if squad == "" || gameState.snakeStates[id].Squad != squad {
+				return SnakeState{}, true
+			}
+		}
+	}
+	return gameState.snakeStates[ranks[0][0]], false
+}
+
 func (gameState *GameState) initializeBoardFromArgs() (bool, *rules.BoardState, error) {
+	// Snake IDs are sorted so that map setup doesn't depend on map iteration order
 	snakeIds := []string{}
 	for _, snakeState := range gameState.snakeStates {
 		snakeIds = append(snakeIds, snakeState.ID)
 	}
-	boardState, err := maps.SetupBoard(maps.StandardMap{}, gameState.ruleset.Settings(), gameState.Width, gameState.Height, snakeIds)
+	sort.Strings(snakeIds)
+	boardState, err := maps.SetupBoard(gameState.gameMap, gameState.ruleset.Settings(), gameState.Width, gameState.Height, snakeIds)
 	if err != nil {
 		return false, nil, fmt.Errorf("error initializing BoardState with map: %w", err)
 	}
+	for i := range boardState.Snakes {
+		boardState.Snakes[i].Squad = gameState.snakeStates[boardState.Snakes[i].ID].Squad
+	}
 	gameOver, boardState, err := gameState.ruleset.Execute(boardState, nil)
 	if err != nil {
 		return false, nil, fmt.Errorf("error initializing BoardState with ruleset: %w", err)
@@ -318,10 +647,16 @@ func (gameState *GameState) createNextBoardState(boardState *rules.BoardState) (
 	wg.Wait()
 	close(stateUpdates)
 
-	var moves []rulesets.SnakeMove
 	for snakeState := range stateUpdates {
 		gameState.snakeStates[snakeState.ID] = snakeState
-		moves = append(moves, rulesets.SnakeMove{ID: snakeState.ID, Move: snakeState.LastMove})
+	}
+
+	// Build moves in board order rather than the order the responses arrived in
+	var moves []rulesets.SnakeMove
+	for _, snake := range boardState.Snakes {
+		if snakeState, ok := gameState.snakeStates[snake.ID]; ok && snake.EliminatedCause == rules.NotEliminated {
+			moves = append(moves, rulesets.SnakeMove{ID: snakeState.ID, Move: snakeState.LastMove})
+		}
 	}
 
 	gameOver, boardState, err := gameState.ruleset.Execute(boardState, moves)
@@ -329,6 +664,11 @@ func (gameState *GameState) createNextBoardState(boardState *rules.BoardState) (
 		return false, boardState, fmt.Errorf("error updating board state from ruleset: %w", err)
 	}
 
+	// the game over stages leave the board as it was, so there is no turn left to play
+	if gameOver {
+		return gameOver, boardState, nil
+	}
+
 	// apply PostUpdateBoard after ruleset operates on snake moves
 	boardState, err = maps.PostUpdateBoard(gameState.gameMap, boardState, gameState.ruleset.Settings())
 	if err != nil {
@@ -344,6 +684,9 @@ func (gameState *GameState) getSnakeUpdate(boardState *rules.BoardState, snakeSt
 	snakeState.StatusCode = 0
 	snakeState.Error = nil
 	snakeState.Latency = 0
+	snakeState.InvalidMove = false
+
+	timeout := gameState.snakeTimeout(snakeState)
 
 	snakeRequest := gameState.getRequestBodyForSnake(boardState, snakeState)
 	requestBody := serialiseSnakeRequest(snakeRequest)
@@ -356,10 +699,19 @@ func (gameState *GameState) getSnakeUpdate(boardState *rules.BoardState, snakeSt
 	}
 	u.Path = path.Join(u.Path, "move")
 	// log.DEBUG.Printf("POST %s: %v", u, string(requestBody))
-	res, responseTime, err := gameState.httpClient.Post(u.String(), "application/json", bytes.NewBuffer(requestBody))
+	res, responseTime, err := gameState.httpClient.WithTimeout(timeout+snakeState.TimeBank).Post(u.String(), "application/json", bytes.NewBuffer(requestBody))
 
 	snakeState.Latency = responseTime
 
+	// Any time spent over the timeout is drawn from the snake's time bank
+	if overrun := responseTime - timeout; overrun > 0 && gameState.TimeBank > 0 {
+		if overrun > snakeState.TimeBank {
+			overrun = snakeState.TimeBank
+		}
+		snakeState.TimeBank -= overrun
+		snakeState.Overrun += overrun
+	}
+
 	if err != nil {
 		log.WARN.Printf(
 			"Request to %v failed\n"+
@@ -410,6 +762,7 @@ func (gameState *GameState) getSnakeUpdate(boardState *rules.BoardState, snakeSt
 				"\tError: invalid move %q, valid moves are \"up\", \"down\", \"left\" or \"right\"\n"+
 				"\tBody: %q\n"+
 				"\tSee https://docs.battlesnake.com/references/api#post-move", u.String(), playerResponse.Move, body)
+		snakeState.InvalidMove = true
 		return snakeState
 	}
 
@@ -418,6 +771,14 @@ func (gameState *GameState) getSnakeUpdate(boardState *rules.BoardState, snakeSt
 	return snakeState
 }
 
+// snakeTimeout returns the move timeout for a snake, falling back to the game timeout.
+func (gameState *GameState) snakeTimeout(snakeState SnakeState) time.Duration {
+	if snakeState.Timeout > 0 {
+		return time.Duration(snakeState.Timeout) * time.Millisecond
+	}
+	return time.Duration(gameState.Timeout) * time.Millisecond
+}
+
 func (gameState *GameState) sendEndRequest(boardState *rules.BoardState, snakeState SnakeState) {
 	snakeRequest := gameState.getRequestBodyForSnake(boardState, snakeState)
 	requestBody := serialiseSnakeRequest(snakeRequest)
@@ -444,6 +805,7 @@ func (gameState *GameState) getRequestBodyForSnake(boardState *rules.BoardState,
 		Board: convertStateToBoard(boardState, gameState.snakeStates),
 		You:   convertRulesSnake(youSnake, snakeState),
 	}
+	request.Game.Timeout = int(gameState.snakeTimeout(snakeState).Milliseconds())
 	return request
 }
 
@@ -464,6 +826,10 @@ func (gameState *GameState) buildSnakesFromOptions() (map[string]SnakeState, err
 	snakes := map[string]SnakeState{}
 	numNames := len(gameState.Names)
 	numURLs := len(gameState.URLs)
+	numSquads := len(gameState.Squads)
+	numTimeouts := len(gameState.SnakeTimeouts)
+	// Snake IDs are derived from the seed so that replaying a seed gives the same board
+	idSource := rand.New(rand.NewSource(gameState.Seed))
 	if numNames > numURLs {
 		numSnakes = numNames
 	} else {
@@ -472,13 +838,27 @@ func (gameState *GameState) buildSnakesFromOptions() (map[string]SnakeState, err
 	for i := int(0); i < numSnakes; i++ {
 		var snakeName string
 		var snakeURL string
+		var snakeSquad string
+		snakeTimeout := gameState.Timeout
 
-		id := uuid.New().String()
+		uuidFromSeed, err := uuid.NewRandomFromReader(idSource)
+		if err != nil {
+			return nil, fmt.Errorf("error generating snake ID: %w", err)
+		}
+		id := uuidFromSeed.String()
 
 		if i < numNames {
 			snakeName = gameState.Names[i]
 		}
 
+		if i < numSquads {
+			snakeSquad = gameState.Squads[i]
+		}
+
+		if i < numTimeouts && gameState.SnakeTimeouts[i] > 0 {
+			snakeTimeout = gameState.SnakeTimeouts[i]
+		}
+
 		if i < numURLs {
 			u, err := url.ParseRequestURI(gameState.URLs[i])
 			if err != nil {
@@ -490,7 +870,8 @@ func (gameState *GameState) buildSnakesFromOptions() (map[string]SnakeState, err
 		}
 
 		snakeState := SnakeState{
-			Name: snakeName, URL: snakeURL, ID: id, LastMove: "up", Character: bodyChars[i%8],
+			Name: snakeName, URL: snakeURL, ID: id, Squad: snakeSquad, LastMove: "up", Character: bodyChars[i%8],
+			Timeout: snakeTimeout, TimeBank: time.Duration(gameState.TimeBank) * time.Millisecond,
 		}
 
 		res, _, err := gameState.httpClient.Get(snakeURL)
@@ -555,6 +936,7 @@ func (gameState *GameState) buildFrameEvent(boardState *rules.BoardState) board.
 		convertedSnake := board.Snake{
 			ID:            snake.ID,
 			Name:          snakeState.Name,
+			Squad:         snake.Squad,
 			Body:          snake.Body,
 			Health:        snake.Health,
 			Color:         snakeState.Color,
@@ -566,7 +948,9 @@ func (gameState *GameState) buildFrameEvent(boardState *rules.BoardState) board.
 			IsEnvironment: false,
 			Latency:       fmt.Sprint(latencyMS),
 		}
-		if snakeState.Error != nil {
+		if snakeState.Error != nil && gameState.TimeBank > 0 && snakeState.TimeBank == 0 && snakeState.Overrun > 0 {
+			convertedSnake.Error = fmt.Sprintf("1:Time bank exhausted after %dms over the timeout", snakeState.Overrun.Milliseconds())
+		} else if snakeState.Error != nil {
 			convertedSnake.Error = "0:Error communicating with server"
 		} else if snakeState.StatusCode != http.StatusOK {
 			convertedSnake.Error = fmt.Sprintf("7:Bad HTTP status code %d", snakeState.StatusCode)
@@ -582,9 +966,10 @@ func (gameState *GameState) buildFrameEvent(boardState *rules.BoardState) board.
 	}
 
 	gameFrame := board.GameFrame{
-		Turn:   boardState.Turn,
-		Snakes: snakes,
-		Food:   boardState.Food,
+		Turn:    boardState.Turn,
+		Snakes:  snakes,
+		Food:    boardState.Food,
+		Hazards: boardState.Hazards,
 	}
 
 	return board.GameEvent{
@@ -606,6 +991,7 @@ func convertRulesSnake(snake rules.Snake, snakeState SnakeState) client.Snake {
 	return client.Snake{
 		ID:      snake.ID,
 		Name:    snakeState.Name,
+		Squad:   snake.Squad,
 		Health:  snake.Health,
 		Body:    client.CoordFromPointArray(snake.Body),
 		Latency: fmt.Sprint(latencyMS),
@@ -632,9 +1018,10 @@ func convertRulesSnakes(snakes []rules.Snake, snakeStates map[string]SnakeState)
 
 func convertStateToBoard(boardState *rules.BoardState, snakeStates map[string]SnakeState) client.Board {
 	return client.Board{
-		Height: boardState.Height,
-		Width:  boardState.Width,
-		Food:   client.CoordFromPointArray(boardState.Food),
-		Snakes: convertRulesSnakes(boardState.Snakes, snakeStates),
+		Height:  boardState.Height,
+		Width:   boardState.Width,
+		Food:    client.CoordFromPointArray(boardState.Food),
+		Hazards: client.CoordFromPointArray(boardState.Hazards),
+		Snakes:  convertRulesSnakes(boardState.Snakes, snakeStates),
 	}
 }
@@ -7,6 +7,10 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +31,7 @@ func buildDefaultGameState() *GameState {
 		Names:           nil,
 		Timeout:         500,
 		GameType:        "standard",
+		MapName:         "standard",
 		Seed:            1,
 		FoodSpawnChance: 15,
 	}
@@ -95,7 +100,7 @@ func TestSettingsRequestSerialization(t *testing.T) {
 	}
 
 	for _, gt := range []string{
-		rules.GameTypeStandard, rules.GameTypeSolo,
+		rules.GameTypeStandard, rules.GameTypeSolo, rules.GameTypeWrapped,
 	} {
 		t.Run(gt, func(t *testing.T) {
 			gameState := buildDefaultGameState()
@@ -116,6 +121,117 @@ func TestSettingsRequestSerialization(t *testing.T) {
 	}
 }
 
+func TestInitializeMap(t *testing.T) {
+	gameState := buildDefaultGameState()
+	gameState.MapName = "hz_rings"
+	require.NoError(t, gameState.Initialize())
+	require.Equal(t, "hz_rings", gameState.gameMap.ID())
+
+	gameState = buildDefaultGameState()
+	gameState.MapName = "missing"
+	require.ErrorIs(t, gameState.Initialize(), rules.ErrorMapNotFound)
+
+	gameState = buildDefaultGameState()
+	gameState.MapFile = "../../maps/testdata/arena.yaml"
+	require.NoError(t, gameState.Initialize())
+	require.Equal(t, "arena", gameState.gameMap.ID())
+	require.Equal(t, 7, gameState.Width)
+	require.Equal(t, 7, gameState.Height)
+
+	gameState = buildDefaultGameState()
+	gameState.MapFile = "../../maps/testdata/arena.yaml"
+	gameState.Width, gameState.Height, gameState.sizeChanged = 7, 7, true
+	require.NoError(t, gameState.Initialize())
+
+	gameState = buildDefaultGameState()
+	gameState.MapFile = "../../maps/testdata/arena.yaml"
+	gameState.Width, gameState.sizeChanged = 9, true
+	require.Error(t, gameState.Initialize())
+
+	gameState = buildDefaultGameState()
+	gameState.MapFile = "missing.yaml"
+	require.Error(t, gameState.Initialize())
+}
+
+func TestInitializeSquads(t *testing.T) {
+	gameState := buildDefaultGameState()
+	gameState.GameType = rules.GameTypeSquad
+	gameState.Squads = []string{"red", "blue"}
+	require.NoError(t, gameState.Initialize())
+
+	gameState = buildDefaultGameState()
+	gameState.Squads = []string{"red", "blue"}
+	require.Error(t, gameState.Initialize())
+}
+
+func TestInitializeSeed(t *testing.T) {
+	gameState := buildDefaultGameState()
+	gameState.Seed = 42
+	require.NoError(t, gameState.Initialize())
+	require.Equal(t, int64(42), gameState.Seed)
+	require.Equal(t, int64(42), gameState.ruleset.Settings().Seed())
+
+	gameState = buildDefaultGameState()
+	gameState.Seed = 0
+	require.NoError(t, gameState.Initialize())
+	require.NotZero(t, gameState.Seed)
+}
+
+func TestSeedReproducesGame(t *testing.T) {
+	playGame := func(seed int64) []*rules.BoardState {
+		gameState := buildDefaultGameState()
+		gameState.Seed = seed
+		gameState.MapName = "snail_mode"
+		gameState.URLs = []string{"http://one.example.com", "http://two.example.com", "http://three.example.com"}
+		require.NoError(t, gameState.Initialize())
+		gameState.httpClient = stubHTTPClient{nil, 200, func(_ string) string { return `{"move": "up"}` }, time.Millisecond}
+
+		var err error
+		gameState.snakeStates, err = gameState.buildSnakesFromOptions()
+		require.NoError(t, err)
+
+		gameOver, boardState, err := gameState.initializeBoardFromArgs()
+		require.NoError(t, err)
+		boardStates := []*rules.BoardState{boardState}
+		for i := 0; i < 10 && !gameOver; i++ {
+			gameOver, boardState, err = gameState.createNextBoardState(boardState)
+			require.NoError(t, err)
+			boardStates = append(boardStates, boardState)
+		}
+		return boardStates
+	}
+
+	first := playGame(7)
+	require.Equal(t, first, playGame(7))
+	require.NotEqual(t, first[0].Snakes[0].ID, playGame(8)[0].Snakes[0].ID)
+}
+
+func TestRunWritesOutputFile(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "game.jsonl")
+
+	gameState := buildDefaultGameState()
+	gameState.URLs = []string{"http://one.example.com", "http://two.example.com"}
+	gameState.MaxTurns = 3
+	gameState.OutputPath = outputPath
+	require.NoError(t, gameState.Initialize())
+	gameState.httpClient = stubHTTPClient{nil, 200, func(_ string) string { return `{"move": "down"}` }, time.Millisecond}
+
+	require.NoError(t, gameState.Run())
+
+	contents, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+
+	// game header, turns 0 to 3 and the result
+	require.Len(t, lines, 6)
+	require.Contains(t, lines[0], `"map":"standard"`)
+	for turn := 0; turn <= 3; turn++ {
+		require.Contains(t, lines[turn+1], fmt.Sprintf(`"turn":%d,`, turn))
+	}
+	require.Contains(t, lines[5], `"seed":1,"placements":[`)
+	require.Contains(t, lines[5], `"moves":["down","down","down"]`)
+}
+
 func TestConvertRulesSnakes(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -132,7 +248,7 @@ func TestConvertRulesSnakes(t *testing.T) {
 		{
 			name: "all properties",
 			snakes: []rules.Snake{
-				{ID: "one", Body: []rules.Point{{X: 3, Y: 3}, {X: 2, Y: 3}}, Health: 100},
+				{ID: "one", Squad: "red", Body: []rules.Point{{X: 3, Y: 3}, {X: 2, Y: 3}}, Health: 100},
 			},
 			state: map[string]SnakeState{
 				"one": {
@@ -151,6 +267,7 @@ func TestConvertRulesSnakes(t *testing.T) {
 				{
 					ID:      "one",
 					Name:    "ONE",
+					Squad:   "red",
 					Latency: "42",
 					Health:  100,
 					Body:    []client.Coord{{X: 3, Y: 3}, {X: 2, Y: 3}},
@@ -228,9 +345,10 @@ func TestBuildFrameEvent(t *testing.T) {
 			expected: board.GameEvent{
 				EventType: board.EVENT_TYPE_FRAME,
 				Data: board.GameFrame{
-					Turn:   0,
-					Snakes: []board.Snake{},
-					Food:   []rules.Point{},
+					Turn:    0,
+					Snakes:  []board.Snake{},
+					Food:    []rules.Point{},
+					Hazards: []rules.Point{},
 				},
 			},
 		},
@@ -239,6 +357,7 @@ func TestBuildFrameEvent(t *testing.T) {
 			boardState: rules.NewBoardState(19, 25).
 				WithTurn(99).
 				WithFood([]rules.Point{{X: 9, Y: 4}}).
+				WithHazards([]rules.Point{{X: 8, Y: 6}}).
 				WithSnakes([]rules.Snake{
 					{
 						ID: "1",
@@ -295,7 +414,8 @@ func TestBuildFrameEvent(t *testing.T) {
 							IsEnvironment: false,
 						},
 					},
-					Food: []rules.Point{{X: 9, Y: 4}},
+					Food:    []rules.Point{{X: 9, Y: 4}},
+					Hazards: []rules.Point{{X: 8, Y: 6}},
 				},
 			},
 		},
@@ -338,7 +458,8 @@ func TestBuildFrameEvent(t *testing.T) {
 							Error:      "0:Error communicating with server",
 						},
 					},
-					Food: []rules.Point{},
+					Food:    []rules.Point{},
+					Hazards: []rules.Point{},
 				},
 			},
 		},
@@ -433,11 +554,12 @@ func TestGetMoveForSnake(t *testing.T) {
 			responseBody:    `{"move": "north"}`,
 			responseLatency: 54 * time.Millisecond,
 			expectedSnakeState: SnakeState{
-				ID:         "one",
-				URL:        "http://example.com",
-				LastMove:   rules.MoveLeft,
-				StatusCode: 200,
-				Latency:    54 * time.Millisecond,
+				ID:          "one",
+				URL:         "http://example.com",
+				LastMove:    rules.MoveLeft,
+				StatusCode:  200,
+				Latency:     54 * time.Millisecond,
+				InvalidMove: true,
 			},
 		},
 		{
@@ -527,6 +649,176 @@ func TestCreateNextBoardState(t *testing.T) {
 
 }
 
+func TestInitializeTimeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		timeout       int
+		snakeTimeouts []int
+		timeBank      int
+		expectedErr   bool
+	}{
+		{"defaults", 500, nil, 0, false},
+		{"snake overrides and time bank", 500, []int{0, 2000}, 5000, false},
+		{"zero timeout", 0, nil, 0, true},
+		{"negative snake timeout", 500, []int{-1}, 0, true},
+		{"negative time bank", 500, nil, -1, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gameState := buildDefaultGameState()
+			gameState.Timeout = test.timeout
+			gameState.SnakeTimeouts = test.snakeTimeouts
+			gameState.TimeBank = test.timeBank
+			err := gameState.Initialize()
+			if test.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildSnakesWithTimeouts(t *testing.T) {
+	gameState := buildDefaultGameState()
+	gameState.URLs = []string{"http://one.example.com", "http://two.example.com"}
+	gameState.SnakeTimeouts = []int{0, 2000}
+	gameState.TimeBank = 1000
+	require.NoError(t, gameState.Initialize())
+	gameState.httpClient = stubHTTPClient{nil, 200, func(_ string) string { return `{}` }, time.Millisecond}
+
+	snakeStates, err := gameState.buildSnakesFromOptions()
+	require.NoError(t, err)
+	timeouts := map[string]int{}
+	for _, snakeState := range snakeStates {
+		timeouts[snakeState.URL] = snakeState.Timeout
+		require.Equal(t, time.Second, snakeState.TimeBank)
+
+		snake := rules.Snake{ID: snakeState.ID, Body: []rules.Point{{X: 1, Y: 1}}}
+		request := gameState.getRequestBodyForSnake(rules.NewBoardState(11, 11).WithSnakes([]rules.Snake{snake}), snakeState)
+		require.Equal(t, snakeState.Timeout, request.Game.Timeout)
+	}
+	require.Equal(t, map[string]int{"http://one.example.com": 500, "http://two.example.com": 2000}, timeouts)
+}
+
+func TestGetMoveForSnakeTimeBank(t *testing.T) {
+	s1 := rules.Snake{ID: "one", Body: []rules.Point{{X: 3, Y: 3}}}
+	boardState := rules.NewBoardState(11, 11).WithSnakes([]rules.Snake{s1})
+
+	tests := []struct {
+		name            string
+		timeBank        time.Duration
+		responseLatency time.Duration
+
+		expectedMove     string
+		expectedTimeBank time.Duration
+		expectedOverrun  time.Duration
+		expectedError    string
+	}{
+		{"within timeout", 250 * time.Millisecond, 80 * time.Millisecond, rules.MoveRight, 250 * time.Millisecond, 0, ""},
+		{"draws from time bank", 250 * time.Millisecond, 150 * time.Millisecond, rules.MoveRight, 200 * time.Millisecond, 50 * time.Millisecond, ""},
+		{"exhausts time bank", 250 * time.Millisecond, 400 * time.Millisecond, rules.MoveLeft, 0, 250 * time.Millisecond, "1:Time bank exhausted after 250ms over the timeout"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gameState := buildDefaultGameState()
+			gameState.TimeBank = int(test.timeBank.Milliseconds())
+			require.NoError(t, gameState.Initialize())
+			gameState.httpClient = stubHTTPClient{nil, 200, func(_ string) string { return `{"move": "right"}` }, test.responseLatency}
+
+			snakeState := SnakeState{ID: s1.ID, URL: "http://example.com", LastMove: rules.MoveLeft, Timeout: 100, TimeBank: test.timeBank}
+			gameState.snakeStates = map[string]SnakeState{s1.ID: snakeState}
+
+			snakeState = gameState.getSnakeUpdate(boardState, snakeState)
+			gameState.snakeStates[s1.ID] = snakeState
+			require.Equal(t, test.expectedMove, snakeState.LastMove)
+			require.Equal(t, test.expectedTimeBank, snakeState.TimeBank)
+			require.Equal(t, test.expectedOverrun, snakeState.Overrun)
+
+			frame := gameState.buildFrameEvent(boardState).Data.(board.GameFrame)
+			require.Equal(t, test.expectedError, frame.Snakes[0].Error)
+		})
+	}
+}
+
+func TestDetermineWinner(t *testing.T) {
+	long := rules.Snake{ID: "long", Health: 50, Body: []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}}}
+	short := rules.Snake{ID: "short", Health: 90, Body: []rules.Point{{X: 3, Y: 1}, {X: 3, Y: 2}}}
+	eliminated := rules.Snake{ID: "short", Health: 90, Body: []rules.Point{{X: 3, Y: 1}}, EliminatedCause: rules.EliminatedByOutOfBounds}
+	snakeStates := map[string]SnakeState{
+		"long":  {ID: "long", Name: "Long", Squad: "red"},
+		"short": {ID: "short", Name: "Short", Squad: "red"},
+	}
+
+	tests := []struct {
+		name           string
+		tieBreak       string
+		snakes         []rules.Snake
+		snakeStates    map[string]SnakeState
+		expectedWinner string
+		expectedDraw   bool
+	}{
+		{"last snake standing", rulesets.DefaultTieBreak, []rules.Snake{long, eliminated}, nil, "Long", false},
+		{"everyone eliminated", rulesets.DefaultTieBreak, []rules.Snake{}, nil, "", true},
+		{"longest snake wins at the turn limit", rulesets.DefaultTieBreak, []rules.Snake{short, long}, nil, "Long", false},
+		{"healthiest snake wins at the turn limit", rules.TieBreakHealth, []rules.Snake{short, long}, nil, "Short", false},
+		{"tied snakes draw", rules.TieBreakKills, []rules.Snake{short, long}, nil, "", true},
+		{"tied squad mates win together", rules.TieBreakKills, []rules.Snake{short, long}, snakeStates, "Short", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gameState := buildDefaultGameState()
+			gameState.MaxTurns = 10
+			gameState.TieBreak = test.tieBreak
+			require.NoError(t, gameState.Initialize())
+			gameState.snakeStates = map[string]SnakeState{
+				"long":  {ID: "long", Name: "Long"},
+				"short": {ID: "short", Name: "Short"},
+			}
+			if test.snakeStates != nil {
+				gameState.snakeStates = test.snakeStates
+			}
+
+			boardState := rules.NewBoardState(11, 11).WithSnakes(test.snakes)
+			boardState.Turn = 10
+			winner, isDraw := gameState.determineWinner(boardState)
+			require.Equal(t, test.expectedWinner, winner.Name)
+			require.Equal(t, test.expectedDraw, isDraw)
+		})
+	}
+}
+
+func TestBuildPlacements(t *testing.T) {
+	gameState := buildDefaultGameState()
+	require.NoError(t, gameState.Initialize())
+	gameState.snakeStates = map[string]SnakeState{
+		"one":   {ID: "one", Name: "One"},
+		"two":   {ID: "two", Name: "Two"},
+		"three": {ID: "three", Name: "Three"},
+	}
+
+	boardState := rules.NewBoardState(11, 11).WithSnakes([]rules.Snake{
+		{ID: "one", Body: []rules.Point{{X: 1, Y: 1}}, EliminatedCause: rules.EliminatedByCollision, EliminatedBy: "three", EliminatedOnTurn: 4},
+		{ID: "two", Body: []rules.Point{{X: 3, Y: 1}}, EliminatedCause: rules.EliminatedByOutOfBounds, EliminatedOnTurn: 4},
+		{ID: "three", Body: []rules.Point{{X: 5, Y: 1}, {X: 5, Y: 2}}},
+	})
+	boardState.Turn = 6
+	stats := newGameStats(boardState)
+	stats.finish(boardState)
+
+	placements := gameState.buildPlacements(boardState, stats)
+	require.Len(t, placements, 3)
+	require.Equal(t, Placement{Place: 1, ID: "three", Name: "Three", Stats: SnakeStats{FinalLength: 2, MaxLength: 2, Kills: 1, TurnsSurvived: 6}}, placements[0])
+	require.Equal(t, Placement{Place: 2, ID: "one", Name: "One", EliminatedCause: rules.EliminatedByCollision, EliminatedBy: "three", EliminatedOnTurn: 4, Stats: SnakeStats{FinalLength: 1, MaxLength: 1, TurnsSurvived: 3}}, placements[1])
+	require.Equal(t, 2, placements[2].Place)
+	require.Equal(t, "two", placements[2].ID)
+
+	exporter := GameExporter{game: gameState.createClientGame(), placements: placements}
+	lines, err := exporter.ConvertToJSON(false)
+	require.NoError(t, err)
+	require.Contains(t, lines[len(lines)-1], `"placements":[{"place":1,"id":"three","name":"Three","stats":{"finalLength":2,"maxLength":2,"foodEaten":0,"kills":1,"turnsSurvived":6,`)
+}
+
 type StubRuleset struct {
 	maxTurns int
 	settings settings.Settings
@@ -567,3 +859,28 @@ func (client stubHTTPClient) Get(url string) (*http.Response, time.Duration, err
 func (client stubHTTPClient) Post(url string, contentType string, body io.Reader) (*http.Response, time.Duration, error) {
 	return client.request(url)
 }
+
+func (client stubHTTPClient) WithTimeout(timeout time.Duration) TimedHttpClient {
+	return timeoutStubHTTPClient{client, timeout}
+}
+
+// timeoutStubHTTPClient fails requests whose latency is over the timeout, like http.Client does
+type timeoutStubHTTPClient struct {
+	stubHTTPClient
+	timeout time.Duration
+}
+
+func (client timeoutStubHTTPClient) request(requestURL string) (*http.Response, time.Duration, error) {
+	if client.latency > client.timeout {
+		return nil, client.timeout, &url.Error{Op: "Post", URL: requestURL, Err: os.ErrDeadlineExceeded}
+	}
+	return client.stubHTTPClient.request(requestURL)
+}
+
+func (client timeoutStubHTTPClient) Get(url string) (*http.Response, time.Duration, error) {
+	return client.request(url)
+}
+
+func (client timeoutStubHTTPClient) Post(url string, contentType string, body io.Reader) (*http.Response, time.Duration, error) {
+	return client.request(url)
+}
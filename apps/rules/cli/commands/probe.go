@@ -0,0 +1,216 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	"rules"
+	"rules/client"
+
+	"github.com/spf13/cobra"
+)
+
+type probeOptions struct {
+	LogPath string
+	Turn    int
+	URL     string
+	Snake   string
+	Timeout int
+}
+
+// probeResult is the move a snake gave when sent a turn from a game log again.
+type probeResult struct {
+	SnakeName   string
+	LoggedMove  string
+	Move        string
+	Latency     time.Duration
+	IsDifferent bool
+}
+
+func NewProbeCommand() *cobra.Command {
+	opts := probeOptions{}
+
+	var probeCmd = &cobra.Command{
+		Use:   "probe",
+		Short: "Send a turn from a saved game to a snake and compare its move.",
+		Long:  "Send the /move request recorded for one turn of a game saved with play --output to a snake, and compare the move it returns with the move made in the game.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(opts.LogPath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			gl, err := readGameLog(f)
+			if err != nil {
+				return fmt.Errorf("error reading game log %s: %w", opts.LogPath, err)
+			}
+
+			httpClient := timedHTTPClient{&http.Client{}}
+			res, err := probeSnake(httpClient, gl, opts)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Turn %d, %s moved %s in the game and %s now (%dms)\n", opts.Turn, res.SnakeName, res.LoggedMove, res.Move, res.Latency.Milliseconds())
+			if res.IsDifferent {
+				return fmt.Errorf("move %q doesn't match the logged move %q", res.Move, res.LoggedMove)
+			}
+			fmt.Fprintln(out, "Moves match")
+			return nil
+		},
+	}
+
+	probeCmd.Flags().StringVar(&opts.LogPath, "log", "", "Path to a game log written with play --output")
+	probeCmd.Flags().IntVar(&opts.Turn, "turn", 0, "Turn to send to the snake")
+	probeCmd.Flags().StringVarP(&opts.URL, "url", "u", "", "URL of the snake to send the turn to")
+	probeCmd.Flags().StringVar(&opts.Snake, "snake", "", "Name or ID of the snake the log was recorded for, checked before the turn is sent")
+	probeCmd.Flags().IntVarP(&opts.Timeout, "timeout", "t", 0, "Request Timeout (defaults to the timeout of the logged game)")
+	_ = probeCmd.MarkFlagRequired("log")
+	_ = probeCmd.MarkFlagRequired("turn")
+	_ = probeCmd.MarkFlagRequired("url")
+
+	probeCmd.Flags().SortFlags = false
+
+	return probeCmd
+}
+
+// probeSnake sends the logged request for a turn to the snake at opts.URL and compares the move
+// it returns with the move made in the logged game.
+func probeSnake(httpClient TimedHttpClient, gl gameLog, opts probeOptions) (probeResult, error) {
+	snakeRequest, err := gl.buildProbeRequest(opts.Turn, opts.Snake)
+	if err != nil {
+		return probeResult{}, err
+	}
+	loggedMove, err := gl.loggedMove(opts.Turn, snakeRequest.You.ID)
+	if err != nil {
+		return probeResult{}, err
+	}
+
+	u, err := url.ParseRequestURI(opts.URL)
+	if err != nil {
+		return probeResult{}, fmt.Errorf("invalid snake URL %q: %w", opts.URL, err)
+	}
+	u.Path = path.Join(u.Path, "move")
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = snakeRequest.Game.Timeout
+	}
+	if timeout > 0 {
+		httpClient = httpClient.WithTimeout(time.Duration(timeout) * time.Millisecond)
+	}
+
+	requestBody := serialiseSnakeRequest(snakeRequest)
+	res, latency, err := httpClient.Post(u.String(), "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return probeResult{}, fmt.Errorf("request to %v failed: %w", u, err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return probeResult{}, fmt.Errorf("failed to read response body from %v: %w", u, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return probeResult{}, fmt.Errorf("got status code %d from %v: %q", res.StatusCode, u, body)
+	}
+	moveResponse := client.MoveResponse{}
+	if err := json.Unmarshal(body, &moveResponse); err != nil {
+		return probeResult{}, fmt.Errorf("failed to decode JSON from %v: %w: %q", u, err, body)
+	}
+
+	return probeResult{
+		SnakeName:   snakeRequest.You.Name,
+		LoggedMove:  loggedMove,
+		Move:        moveResponse.Move,
+		Latency:     latency,
+		IsDifferent: moveResponse.Move != loggedMove,
+	}, nil
+}
+
+// buildProbeRequest returns the request logged for a turn. The log only has the requests sent to one snake,
+// and other snakes may have been sent a different board, so the turn can only be probed as that snake.
+func (gl gameLog) buildProbeRequest(turn int, snakeRef string) (client.SnakeRequest, error) {
+	snakeRequest, ok := gl.findTurn(turn)
+	if !ok {
+		return client.SnakeRequest{}, fmt.Errorf("turn %d isn't in the game log", turn)
+	}
+	if snakeRef == "" || snakeRef == snakeRequest.You.ID || snakeRef == snakeRequest.You.Name {
+		return snakeRequest, nil
+	}
+
+	for _, snake := range snakeRequest.Board.Snakes {
+		if snake.ID == snakeRef || snake.Name == snakeRef {
+			return client.SnakeRequest{}, fmt.Errorf("the game log only has the requests sent to %s, not %s", snakeRequest.You.Name, snake.Name)
+		}
+	}
+	return client.SnakeRequest{}, fmt.Errorf("no snake called %q on the board on turn %d", snakeRef, turn)
+}
+
+// loggedMove finds the move a snake made on a turn. Moves are taken from the game result when the log has one,
+// otherwise they are worked out from where the snake's head was on the next turn.
+func (gl gameLog) loggedMove(turn int, snakeID string) (string, error) {
+	if gl.result != nil {
+		for _, placement := range gl.result.Placements {
+			if placement.ID == snakeID && turn < len(placement.Moves) {
+				return placement.Moves[turn], nil
+			}
+		}
+	}
+
+	current, _ := gl.findTurn(turn)
+	next, ok := gl.findTurn(turn + 1)
+	if !ok {
+		return "", fmt.Errorf("no move was logged for turn %d", turn)
+	}
+	from, fromOK := findClientSnake(current.Board.Snakes, snakeID)
+	to, toOK := findClientSnake(next.Board.Snakes, snakeID)
+	if !fromOK || !toOK {
+		return "", fmt.Errorf("no move was logged for turn %d, the snake isn't on the board on turn %d", turn, turn+1)
+	}
+	return moveBetween(from.Head, to.Head, current.Board.Width, current.Board.Height)
+}
+
+func (gl gameLog) findTurn(turn int) (client.SnakeRequest, bool) {
+	for _, snakeRequest := range gl.snakeRequests {
+		if snakeRequest.Turn == turn {
+			return snakeRequest, true
+		}
+	}
+	return client.SnakeRequest{}, false
+}
+
+func findClientSnake(snakes []client.Snake, snakeID string) (client.Snake, bool) {
+	for _, snake := range snakes {
+		if snake.ID == snakeID {
+			return snake, true
+		}
+	}
+	return client.Snake{}, false
+}
+
+// moveBetween returns the move from one head position to the next, including moves that wrap around the board.
+func moveBetween(from, to client.Coord, width, height int) (string, error) {
+	dx := (to.X - from.X + width) % width
+	dy := (to.Y - from.Y + height) % height
+	switch {
+	case dx == 1 && dy == 0:
+		return rules.MoveRight, nil
+	case dx == width-1 && dy == 0:
+		return rules.MoveLeft, nil
+	case dx == 0 && dy == 1:
+		return rules.MoveUp, nil
+	case dx == 0 && dy == height-1:
+		return rules.MoveDown, nil
+	}
+	return "", fmt.Errorf("can't work out the move from %v to %v", from, to)
+}
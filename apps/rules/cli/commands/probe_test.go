@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"rules"
+	"rules/client"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeSnake(t *testing.T) {
+	tests := []struct {
+		name     string
+		finished bool
+		opts     probeOptions
+		move     string
+		expected probeResult
+		err      string
+	}{
+		{
+			name:     "same move",
+			opts:     probeOptions{Turn: 0},
+			move:     "up",
+			expected: probeResult{SnakeName: "One", LoggedMove: "up", Move: "up"},
+		},
+		{
+			name:     "different move",
+			opts:     probeOptions{Turn: 1},
+			move:     "left",
+			expected: probeResult{SnakeName: "One", LoggedMove: "right", Move: "left", IsDifferent: true},
+		},
+		{
+			name:     "logged snake",
+			opts:     probeOptions{Turn: 0, Snake: "one"},
+			move:     "up",
+			expected: probeResult{SnakeName: "One", LoggedMove: "up", Move: "up"},
+		},
+		{
+			name:     "move from the result",
+			finished: true,
+			opts:     probeOptions{Turn: 2, Snake: "One"},
+			move:     "left",
+			expected: probeResult{SnakeName: "One", LoggedMove: "down", Move: "left", IsDifferent: true},
+		},
+		{
+			name: "other snake",
+			opts: probeOptions{Turn: 0, Snake: "Two"},
+			err:  "the game log only has the requests sent to One, not Two",
+		},
+		{
+			name: "last turn",
+			opts: probeOptions{Turn: 2},
+			err:  "no move was logged for turn 2",
+		},
+		{
+			name: "missing turn",
+			opts: probeOptions{Turn: 5},
+			err:  "turn 5 isn't in the game log",
+		},
+		{
+			name: "unknown snake",
+			opts: probeOptions{Turn: 0, Snake: "three"},
+			err:  `no snake called "three" on the board on turn 0`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requestedURL string
+			httpClient := stubHTTPClient{nil, 200, func(url string) string {
+				requestedURL = url
+				return `{"move": "` + test.move + `"}`
+			}, time.Millisecond}
+			test.opts.URL = "http://example.com/snake"
+
+			gl, err := readGameLog(strings.NewReader(buildTestGameLog(t, test.finished)))
+			require.NoError(t, err)
+
+			res, err := probeSnake(httpClient, gl, test.opts)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "http://example.com/snake/move", requestedURL)
+			test.expected.Latency = time.Millisecond
+			require.Equal(t, test.expected, res)
+		})
+	}
+}
+
+func TestProbeSnakeErrors(t *testing.T) {
+	gl, err := readGameLog(strings.NewReader(buildTestGameLog(t, true)))
+	require.NoError(t, err)
+
+	_, err = probeSnake(stubHTTPClient{nil, 500, func(_ string) string { return "oops" }, time.Millisecond}, gl, probeOptions{URL: "http://example.com"})
+	require.EqualError(t, err, `got status code 500 from http://example.com/move: "oops"`)
+
+	_, err = probeSnake(stubHTTPClient{nil, 200, func(_ string) string { return "up" }, time.Millisecond}, gl, probeOptions{URL: "http://example.com"})
+	require.ErrorContains(t, err, "failed to decode JSON from http://example.com/move")
+
+	_, err = probeSnake(stubHTTPClient{nil, 200, func(_ string) string { return `{"move": "up"}` }, time.Second}, gl, probeOptions{URL: "http://example.com", Timeout: 100})
+	require.ErrorContains(t, err, "request to http://example.com/move failed")
+}
+
+func TestMoveBetween(t *testing.T) {
+	tests := []struct {
+		from, to client.Coord
+		expected string
+	}{
+		{client.Coord{X: 3, Y: 3}, client.Coord{X: 3, Y: 4}, rules.MoveUp},
+		{client.Coord{X: 3, Y: 3}, client.Coord{X: 3, Y: 2}, rules.MoveDown},
+		{client.Coord{X: 3, Y: 3}, client.Coord{X: 2, Y: 3}, rules.MoveLeft},
+		{client.Coord{X: 3, Y: 3}, client.Coord{X: 4, Y: 3}, rules.MoveRight},
+		{client.Coord{X: 0, Y: 3}, client.Coord{X: 6, Y: 3}, rules.MoveLeft},
+		{client.Coord{X: 3, Y: 6}, client.Coord{X: 3, Y: 0}, rules.MoveUp},
+		{client.Coord{X: 3, Y: 3}, client.Coord{X: 4, Y: 4}, ""},
+	}
+
+	for _, test := range tests {
+		move, err := moveBetween(test.from, test.to, 7, 7)
+		if test.expected == "" {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.expected, move)
+	}
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"rules"
+)
+
+const (
+	RenderNone  = ""
+	RenderASCII = "ascii"
+
+	ansiReset = "\x1b[0m"
+	ansiDim   = "\x1b[2m"
+)
+
+// renderBoard draws the board top row first, followed by a legend with the status of each snake.
+// Snake heads are labelled A, B, C... in board order and bodies use the snake's Character.
+func (gameState *GameState) renderBoard(w io.Writer, boardState *rules.BoardState) {
+	type cell struct {
+		glyph string
+		color string
+	}
+	grid := make([][]cell, boardState.Height)
+	for y := range grid {
+		grid[y] = make([]cell, boardState.Width)
+		for x := range grid[y] {
+			grid[y][x] = cell{glyph: "."}
+		}
+	}
+	set := func(p rules.Point, c cell) {
+		if p.X >= 0 && p.X < boardState.Width && p.Y >= 0 && p.Y < boardState.Height {
+			grid[p.Y][p.X] = c
+		}
+	}
+
+	for _, p := range boardState.Hazards {
+		set(p, cell{glyph: "#", color: ansiDim})
+	}
+	for _, p := range boardState.Food {
+		set(p, cell{glyph: "*"})
+	}
+	for i, snake := range boardState.Snakes {
+		if snake.EliminatedCause != rules.NotEliminated {
+			continue
+		}
+		snakeState := gameState.snakeStates[snake.ID]
+		color := ansiColor(snakeState.Color)
+		// Draw tail first so the head is on top when the body overlaps itself
+		for j := len(snake.Body) - 1; j > 0; j-- {
+			set(snake.Body[j], cell{glyph: string(snakeState.Character), color: color})
+		}
+		if len(snake.Body) > 0 {
+			set(snake.Body[0], cell{glyph: snakeLabel(i), color: color})
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Turn %d\n", boardState.Turn)
+	for y := boardState.Height - 1; y >= 0; y-- {
+		for x, c := range grid[y] {
+			if x > 0 {
+				sb.WriteString(" ")
+			}
+			if gameState.RenderColor && c.color != "" {
+				sb.WriteString(c.color + c.glyph + ansiReset)
+			} else {
+				sb.WriteString(c.glyph)
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	for i, snake := range boardState.Snakes {
+		snakeState := gameState.snakeStates[snake.ID]
+		name := snakeState.Name
+		if name == "" {
+			name = snake.ID
+		}
+		glyphs := snakeLabel(i) + " " + string(snakeState.Character)
+		if color := ansiColor(snakeState.Color); gameState.RenderColor && color != "" {
+			glyphs = color + glyphs + ansiReset
+		}
+		if snake.EliminatedCause != rules.NotEliminated {
+			fmt.Fprintf(&sb, "%s %-20s eliminated by %s on turn %d\n", glyphs, name, snake.EliminatedCause, snake.EliminatedOnTurn)
+			continue
+		}
+		fmt.Fprintf(&sb, "%s %-20s health %3d  length %3d  latency %4dms\n", glyphs, name, snake.Health, len(snake.Body), snakeState.Latency.Milliseconds())
+	}
+	sb.WriteString("\n")
+
+	fmt.Fprint(w, sb.String())
+}
+
+func snakeLabel(i int) string {
+	return string(rune('A' + i%26))
+}
+
+// ansiColor converts a snake's "#rrggbb" colour into an ANSI 24-bit foreground colour code.
+// It returns an empty string if the colour can't be parsed.
+func ansiColor(hexColor string) string {
+	hexColor = strings.TrimPrefix(hexColor, "#")
+	if len(hexColor) != 6 {
+		return ""
+	}
+	rgb, err := strconv.ParseUint(hexColor, 16, 32)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", rgb>>16, (rgb>>8)&0xff, rgb&0xff)
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"rules"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderBoard(t *testing.T) {
+	gameState := buildDefaultGameState()
+	gameState.snakeStates = map[string]SnakeState{
+		"one": {ID: "one", Name: "One", Character: '■', Color: "#ff8000", Latency: 42 * time.Millisecond},
+		"two": {ID: "two", Name: "Two", Character: '●'},
+	}
+	boardState := rules.NewBoardState(4, 3).
+		WithFood([]rules.Point{{X: 3, Y: 2}}).
+		WithHazards([]rules.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}).
+		WithSnakes([]rules.Snake{
+			{ID: "one", Health: 87, Body: []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 0}, {X: 2, Y: 0}}},
+			{ID: "two", Health: 20, Body: []rules.Point{{X: 3, Y: 0}}, EliminatedCause: rules.EliminatedByOutOfBounds, EliminatedOnTurn: 4},
+		})
+	boardState.Turn = 5
+
+	var buf bytes.Buffer
+	gameState.renderBoard(&buf, boardState)
+	require.Equal(t, "Turn 5\n"+
+		". . . *\n"+
+		". A . .\n"+
+		"# ■ ■ .\n"+
+		"A ■ One                  health  87  length   3  latency   42ms\n"+
+		"B ● Two                  eliminated by wall-collision on turn 4\n\n", buf.String())
+
+	gameState.RenderColor = true
+	buf.Reset()
+	gameState.renderBoard(&buf, boardState)
+	require.Contains(t, buf.String(), "\x1b[2m#\x1b[0m \x1b[38;2;255;128;0m■\x1b[0m")
+	require.Contains(t, buf.String(), "\x1b[38;2;255;128;0mA ■\x1b[0m One")
+	require.Contains(t, buf.String(), "B ● Two")
+}
+
+func TestAnsiColor(t *testing.T) {
+	tests := []struct {
+		color    string
+		expected string
+	}{
+		{"#ff8000", "\x1b[38;2;255;128;0m"},
+		{"00FF00", "\x1b[38;2;0;255;0m"},
+		{"", ""},
+		{"#fff", ""},
+		{"#gggggg", ""},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, ansiColor(test.color), test.color)
+	}
+}
+
+func TestInitializeRender(t *testing.T) {
+	gameState := buildDefaultGameState()
+	gameState.Render = RenderASCII
+	require.NoError(t, gameState.Initialize())
+
+	gameState = buildDefaultGameState()
+	gameState.Render = "svg"
+	require.Error(t, gameState.Initialize())
+}
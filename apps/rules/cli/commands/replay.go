@@ -0,0 +1,278 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"rules"
+	"rules/board"
+	"rules/client"
+
+	"github.com/spf13/cobra"
+	log "github.com/spf13/jwalterweatherman"
+)
+
+type replayOptions struct {
+	Speed     float64
+	StartTurn int
+	BoardAddr string
+	BoardURL  string
+}
+
+// gameLog is a game read back from the JSON lines written by GameExporter.
+type gameLog struct {
+	game          client.Game
+	snakeRequests []client.SnakeRequest
+	result        *result
+}
+
+func NewReplayCommand() *cobra.Command {
+	opts := replayOptions{}
+
+	var replayCmd = &cobra.Command{
+		Use:   "replay <file>",
+		Short: "Play back a saved game in the board viewer.",
+		Long:  "Play back a game saved with play --output in the board viewer, as if it was being played live.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			gl, err := readGameLog(f)
+			if err != nil {
+				return fmt.Errorf("error reading game log %s: %w", args[0], err)
+			}
+			return replayGame(gl, opts)
+		},
+	}
+
+	replayCmd.Flags().Float64Var(&opts.Speed, "speed", 10, "Playback speed in turns per second (0 to send every turn at once)")
+	replayCmd.Flags().IntVar(&opts.StartTurn, "start-turn", 0, "Turn to start playing back from")
+	replayCmd.Flags().StringVar(&opts.BoardAddr, "board-addr", board.DefaultAddr, "Address for the board server to listen on")
+	replayCmd.Flags().StringVar(&opts.BoardURL, "board-url", "", "Base URL of a hosted game board to use instead of the one built in")
+
+	return replayCmd
+}
+
+// readGameLog parses the game, turn and result lines of a game log.
+// Logs from games that didn't finish have no result line.
+func readGameLog(r io.Reader) (gameLog, error) {
+	gl := gameLog{}
+
+	scanner := bufio.NewScanner(r)
+	// Turns on large boards can be longer than the default maximum line length
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		lineNumber++
+
+		if lineNumber == 1 {
+			if err := json.Unmarshal(line, &gl.game); err != nil {
+				return gl, fmt.Errorf("line %d: invalid game: %w", lineNumber, err)
+			}
+			continue
+		}
+
+		// Turns always have a board, which the result doesn't
+		var probe struct {
+			Board *client.Board `json:"board"`
+		}
+		if err := json.Unmarshal(line, &probe); err != nil {
+			return gl, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if probe.Board == nil {
+			gameResult := result{}
+			if err := json.Unmarshal(line, &gameResult); err != nil {
+				return gl, fmt.Errorf("line %d: invalid result: %w", lineNumber, err)
+			}
+			gl.result = &gameResult
+			continue
+		}
+
+		snakeRequest := client.SnakeRequest{}
+		if err := json.Unmarshal(line, &snakeRequest); err != nil {
+			return gl, fmt.Errorf("line %d: invalid turn: %w", lineNumber, err)
+		}
+		gl.snakeRequests = append(gl.snakeRequests, snakeRequest)
+	}
+	if err := scanner.Err(); err != nil {
+		return gl, err
+	}
+
+	if lineNumber == 0 {
+		return gl, fmt.Errorf("game log is empty")
+	}
+	if len(gl.snakeRequests) == 0 {
+		return gl, fmt.Errorf("game log has no turns")
+	}
+	return gl, nil
+}
+
+// gameID is the ID the game is served under by the board server.
+// Games played locally don't have an ID, so they are served as "replay".
+func (gl gameLog) gameID() string {
+	if gl.game.ID == "" {
+		return "replay"
+	}
+	return gl.game.ID
+}
+
+// buildBoardGame describes the logged game for the board viewer.
+func (gl gameLog) buildBoardGame() board.Game {
+	firstBoard := gl.snakeRequests[0].Board
+	return board.Game{
+		Status: "running",
+		Width:  firstBoard.Width,
+		Height: firstBoard.Height,
+		Ruleset: map[string]string{
+			rules.ParamGameType: gl.game.Ruleset.Name,
+		},
+		SnakeTimeout: gl.game.Timeout,
+		Source:       gl.game.Source,
+		RulesetName:  gl.game.Ruleset.Name,
+		RulesStages:  []string{},
+		Map:          gl.game.Map,
+	}
+}
+
+// buildFrameEvents converts each logged turn from startTurn onwards into a board frame.
+// The log only includes snakes that are still alive, so eliminated snakes are kept at their last
+// position and marked with how they were eliminated, as the live board does.
+func (gl gameLog) buildFrameEvents(startTurn int) []board.GameEvent {
+	deaths := map[string]*board.Death{}
+	if gl.result != nil {
+		for _, placement := range gl.result.Placements {
+			if placement.EliminatedCause != rules.NotEliminated {
+				deaths[placement.ID] = &board.Death{
+					Cause:        placement.EliminatedCause,
+					Turn:         placement.EliminatedOnTurn,
+					EliminatedBy: placement.EliminatedBy,
+				}
+			}
+		}
+	}
+
+	var snakeOrder []string
+	lastSeen := map[string]board.Snake{}
+	events := []board.GameEvent{}
+	for _, snakeRequest := range gl.snakeRequests {
+		alive := map[string]bool{}
+		for _, snake := range snakeRequest.Board.Snakes {
+			if _, ok := lastSeen[snake.ID]; !ok {
+				snakeOrder = append(snakeOrder, snake.ID)
+			}
+			alive[snake.ID] = true
+			lastSeen[snake.ID] = convertClientSnake(snake)
+		}
+
+		snakes := []board.Snake{}
+		for _, id := range snakeOrder {
+			snake := lastSeen[id]
+			if !alive[id] {
+				snake.Death = deaths[id]
+				if snake.Death == nil {
+					snake.Death = &board.Death{Cause: "eliminated", Turn: snakeRequest.Turn}
+				}
+			}
+			snakes = append(snakes, snake)
+		}
+
+		if snakeRequest.Turn < startTurn {
+			continue
+		}
+		events = append(events, board.GameEvent{
+			EventType: board.EVENT_TYPE_FRAME,
+			Data: board.GameFrame{
+				Turn:    snakeRequest.Turn,
+				Snakes:  snakes,
+				Food:    client.PointFromCoordArray(snakeRequest.Board.Food),
+				Hazards: client.PointFromCoordArray(snakeRequest.Board.Hazards),
+			},
+		})
+	}
+	return events
+}
+
+func convertClientSnake(snake client.Snake) board.Snake {
+	return board.Snake{
+		ID:         snake.ID,
+		Name:       snake.Name,
+		Squad:      snake.Squad,
+		Body:       client.PointFromCoordArray(snake.Body),
+		Health:     snake.Health,
+		Color:      snake.Customizations.Color,
+		HeadType:   snake.Customizations.Head,
+		TailType:   snake.Customizations.Tail,
+		Latency:    snake.Latency,
+		Shout:      snake.Shout,
+		StatusCode: 200,
+	}
+}
+
+// replayGame serves the logged game through a BoardServer, sending frames at the requested speed.
+func replayGame(gl gameLog, opts replayOptions) error {
+	if opts.Speed < 0 {
+		return fmt.Errorf("speed can't be negative, got %v", opts.Speed)
+	}
+	events := gl.buildFrameEvents(opts.StartTurn)
+	if len(events) == 0 {
+		return fmt.Errorf("game log has no turns from turn %d onwards", opts.StartTurn)
+	}
+
+	boardGame := gl.buildBoardGame()
+	boardServer := board.NewBoardServer(opts.BoardAddr)
+	if err := boardServer.AddGame(gl.gameID(), boardGame); err != nil {
+		return err
+	}
+	serverURL, err := boardServer.Listen()
+	if err != nil {
+		return fmt.Errorf("error starting HTTP server: %w", err)
+	}
+	defer boardServer.Shutdown()
+	log.INFO.Printf("Board server listening on %s", serverURL)
+	openBoard(opts.BoardURL, serverURL, gl.gameID())
+
+	for !boardServer.IsConnected(gl.gameID()) {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	var ticker <-chan time.Time
+	if opts.Speed > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / opts.Speed))
+		defer t.Stop()
+		ticker = t.C
+	}
+	for i, event := range events {
+		if ticker != nil && i > 0 {
+			<-ticker
+		}
+		boardServer.SendEvent(gl.gameID(), event)
+	}
+
+	boardServer.SendEvent(gl.gameID(), board.GameEvent{
+		EventType: board.EVENT_TYPE_GAME_END,
+		Data:      boardGame,
+	})
+	boardServer.EndGame(gl.gameID())
+
+	if gl.result != nil {
+		if gl.result.IsDraw {
+			log.INFO.Printf("Replayed game with seed %d. It was a draw.", gl.result.Seed)
+		} else {
+			log.INFO.Printf("Replayed game with seed %d. %v was the winner.", gl.result.Seed, gl.result.WinnerName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"rules"
+	"rules/board"
+	"rules/client"
+
+	"github.com/stretchr/testify/require"
+)
+
+func buildTestGameLog(t *testing.T, finished bool) string {
+	one := client.Snake{ID: "one", Name: "One", Health: 100, Head: client.Coord{X: 1, Y: 1}, Body: []client.Coord{{X: 1, Y: 1}}, Latency: "12", Customizations: client.Customizations{Color: "#ff0000"}}
+	two := client.Snake{ID: "two", Name: "Two", Health: 100, Head: client.Coord{X: 5, Y: 5}, Body: []client.Coord{{X: 5, Y: 5}}}
+
+	ge := GameExporter{
+		game:          client.Game{ID: "game-id", Ruleset: client.Ruleset{Name: rules.GameTypeStandard}, Map: "standard", Timeout: 500},
+		snakeRequests: make([]client.SnakeRequest, 0),
+		seed:          3,
+	}
+	ge.AddSnakeRequest(client.SnakeRequest{Turn: 0, Board: client.Board{Width: 7, Height: 7, Snakes: []client.Snake{one, two}, Food: []client.Coord{{X: 3, Y: 3}}}, You: one})
+	one.Head = client.Coord{X: 1, Y: 2}
+	one.Body = []client.Coord{one.Head}
+	ge.AddSnakeRequest(client.SnakeRequest{Turn: 1, Board: client.Board{Width: 7, Height: 7, Snakes: []client.Snake{one}, Hazards: []client.Coord{{X: 0, Y: 0}}}, You: one})
+	one.Head = client.Coord{X: 2, Y: 2}
+	one.Body = []client.Coord{one.Head}
+	ge.AddSnakeRequest(client.SnakeRequest{Turn: 2, Board: client.Board{Width: 7, Height: 7, Snakes: []client.Snake{one}}, You: one})
+	if finished {
+		ge.Finish(SnakeState{ID: "one", Name: "One"}, false, []Placement{
+			{Place: 1, ID: "one", Name: "One", Moves: []string{"up", "right", "down"}},
+			{Place: 2, ID: "two", Name: "Two", EliminatedCause: rules.EliminatedByOutOfBounds, EliminatedOnTurn: 1, Moves: []string{"left"}},
+		})
+	}
+
+	var buf bytes.Buffer
+	_, err := ge.FlushToFile(&buf)
+	require.NoError(t, err)
+	return buf.String()
+}
+
+func TestReadGameLog(t *testing.T) {
+	gl, err := readGameLog(strings.NewReader(buildTestGameLog(t, true)))
+	require.NoError(t, err)
+	require.Equal(t, "game-id", gl.game.ID)
+	require.Len(t, gl.snakeRequests, 3)
+	require.NotNil(t, gl.result)
+	require.Equal(t, int64(3), gl.result.Seed)
+
+	require.Equal(t, board.Game{
+		Status:       "running",
+		Width:        7,
+		Height:       7,
+		Ruleset:      map[string]string{rules.ParamGameType: rules.GameTypeStandard},
+		SnakeTimeout: 500,
+		RulesetName:  rules.GameTypeStandard,
+		RulesStages:  []string{},
+		Map:          "standard",
+	}, gl.buildBoardGame())
+
+	// Logs from unfinished games can still be replayed
+	gl, err = readGameLog(strings.NewReader(buildTestGameLog(t, false)))
+	require.NoError(t, err)
+	require.Len(t, gl.snakeRequests, 3)
+	require.Nil(t, gl.result)
+}
+
+func TestReadGameLogErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"empty", ""},
+		{"invalid game", "{\n"},
+		{"no turns", `{"id": "game-id"}` + "\n"},
+		{"invalid turn", `{"id": "game-id"}` + "\n" + `{"board": []}` + "\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := readGameLog(strings.NewReader(test.contents))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestBuildReplayFrameEvents(t *testing.T) {
+	gl, err := readGameLog(strings.NewReader(buildTestGameLog(t, true)))
+	require.NoError(t, err)
+
+	events := gl.buildFrameEvents(0)
+	require.Len(t, events, 3)
+
+	frame := events[0].Data.(board.GameFrame)
+	require.Equal(t, board.EVENT_TYPE_FRAME, events[0].EventType)
+	require.Equal(t, 0, frame.Turn)
+	require.Equal(t, []rules.Point{{X: 3, Y: 3}}, frame.Food)
+	require.Equal(t, board.Snake{
+		ID: "one", Name: "One", Body: []rules.Point{{X: 1, Y: 1}}, Health: 100, Color: "#ff0000", Latency: "12", StatusCode: 200,
+	}, frame.Snakes[0])
+	require.Nil(t, frame.Snakes[1].Death)
+
+	// Eliminated snakes stay on the board with the cause from the result
+	frame = events[1].Data.(board.GameFrame)
+	require.Equal(t, []rules.Point{{X: 0, Y: 0}}, frame.Hazards)
+	require.Len(t, frame.Snakes, 2)
+	require.Nil(t, frame.Snakes[0].Death)
+	require.Equal(t, &board.Death{Cause: rules.EliminatedByOutOfBounds, Turn: 1}, frame.Snakes[1].Death)
+	require.Equal(t, []rules.Point{{X: 5, Y: 5}}, frame.Snakes[1].Body)
+
+	// Without a result the cause of elimination isn't known
+	gl, err = readGameLog(strings.NewReader(buildTestGameLog(t, false)))
+	require.NoError(t, err)
+	frame = gl.buildFrameEvents(0)[1].Data.(board.GameFrame)
+	require.Equal(t, &board.Death{Cause: "eliminated", Turn: 1}, frame.Snakes[1].Death)
+
+	// Playback can start part way through the game
+	events = gl.buildFrameEvents(1)
+	require.Len(t, events, 2)
+	require.Equal(t, 1, events[0].Data.(board.GameFrame).Turn)
+	require.Empty(t, gl.buildFrameEvents(3))
+}
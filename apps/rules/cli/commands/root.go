@@ -20,6 +20,14 @@ var rootCmd = &cobra.Command{
 
 func Execute() {
 	rootCmd.AddCommand(NewPlayCommand())
+	rootCmd.AddCommand(NewMapsCommand())
+	rootCmd.AddCommand(NewReplayCommand())
+	rootCmd.AddCommand(NewDebugCommand())
+	rootCmd.AddCommand(NewProbeCommand())
+	rootCmd.AddCommand(NewTournamentCommand())
+	rootCmd.AddCommand(NewLadderCommand())
+	rootCmd.AddCommand(NewBenchCommand())
+	rootCmd.AddCommand(NewServeCommand())
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
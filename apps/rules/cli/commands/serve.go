@@ -0,0 +1,510 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"rules"
+	"rules/board"
+
+	"github.com/google/uuid"
+	"github.com/rs/cors"
+	"github.com/spf13/cobra"
+	log "github.com/spf13/jwalterweatherman"
+)
+
+// Statuses of games hosted by the serve command
+const (
+	GameStatusCreated   = "created"
+	GameStatusRunning   = "running"
+	GameStatusFinished  = "finished"
+	GameStatusCancelled = "cancelled"
+	GameStatusFailed    = "failed"
+)
+
+const (
+	// maxCreateGameRequestSize limits the size of a request to create a game
+	maxCreateGameRequestSize = 1 << 20
+	// defaultKeepGames is how many stopped games are kept before the oldest are removed
+	defaultKeepGames = 100
+)
+
+// createGameRequest is the body of a request to create a game. Anything left out uses the serve command's flags.
+type createGameRequest struct {
+	Snakes   []createGameSnake `json:"snakes"`
+	Width    int               `json:"width"`
+	Height   int               `json:"height"`
+	Map      string            `json:"map"`
+	Ruleset  string            `json:"ruleset"`
+	Seed     int64             `json:"seed"`
+	Timeout  int               `json:"timeout"`
+	MaxTurns int               `json:"maxTurns"`
+}
+
+type createGameSnake struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Squad string `json:"squad,omitempty"`
+}
+
+// gameStatus is how a hosted game is described by the API.
+type gameStatus struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Turn      int       `json:"turn"`
+	Seed      int64     `json:"seed"`
+	CreatedAt time.Time `json:"createdAt"`
+	Error     string    `json:"error,omitempty"`
+	Result    *result   `json:"result,omitempty"`
+}
+
+// engineGame is a game hosted by the engine server.
+type engineGame struct {
+	gameStatus
+	gameState *GameState
+	logPath   string
+	cancel    context.CancelFunc
+	done      chan struct{} // closed once the game has stopped, after it has finished or been cancelled
+}
+
+// engineServer runs games on request over HTTP, streaming their frames with the board server's websocket API.
+type engineServer struct {
+	template GameState
+	logDir   string
+
+	board      *board.BoardServer
+	httpClient TimedHttpClient // replaces the snakes' HTTP client in tests
+	keepGames  int             // stopped games kept before the oldest are removed, 0 to keep every game
+
+	mutex sync.Mutex
+	games map[string]*engineGame
+}
+
+func NewServeCommand() *cobra.Command {
+	gameState := &GameState{}
+	var addr string
+	var logDir string
+	var keepGames int
+
+	var serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Run an HTTP API for creating and running games.",
+		Long:  "Run an HTTP API that creates games, runs them, streams their frames over a websocket and serves their results and logs.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if logDir == "" {
+				dir, err := os.MkdirTemp("", "battlesnake-serve-")
+				if err != nil {
+					return err
+				}
+				defer os.RemoveAll(dir)
+				logDir = dir
+			} else if err := os.MkdirAll(logDir, 0755); err != nil {
+				return err
+			}
+
+			gameState.sizeChanged = boardSizeChanged(cmd)
+			engine := newEngineServer(*gameState, logDir)
+			engine.keepGames = keepGames
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			httpServer := &http.Server{Handler: engine.Handler()}
+			go func() {
+				if err := httpServer.Serve(listener); err != http.ErrServerClosed {
+					log.ERROR.Printf("Error in engine HTTP server: %v", err)
+				}
+			}()
+			log.INFO.Printf("Engine API listening on http://%s", listener.Addr())
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			<-ctx.Done()
+
+			log.INFO.Printf("Cancelling running games and shutting down")
+			engine.Shutdown()
+			return httpServer.Shutdown(context.Background())
+		},
+	}
+
+	serveCmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().StringVarP(&logDir, "output", "o", "", "Directory to keep game logs in (defaults to a temporary directory removed on exit)")
+	serveCmd.Flags().IntVar(&keepGames, "keep-games", defaultKeepGames, "Number of finished, cancelled or failed games to keep before the oldest are removed along with their logs (0 to keep every game)")
+	addGameFlags(serveCmd, gameState)
+
+	serveCmd.Flags().SortFlags = false
+
+	return serveCmd
+}
+
+func newEngineServer(template GameState, logDir string) *engineServer {
+	return &engineServer{
+		template:  template,
+		logDir:    logDir,
+		board:     board.NewBoardServer(""),
+		keepGames: defaultKeepGames,
+		games:     map[string]*engineGame{},
+	}
+}
+
+// Handler serves the engine API:
+//
+//	POST   /games                       create a game
+//	GET    /games                       list games
+//	GET    /games/{id}                  the game's status, and its result once it has finished
+//	DELETE /games/{id}                  cancel the game if it hasn't stopped, and remove it along with its log
+//	POST   /games/{id}/start            start running the game
+//	POST   /games/{id}/cancel           stop a game that is running or hasn't started
+//	GET    /games/{id}/game             the game's details in the board server's format
+//	GET    /games/{id}/events           a websocket streaming the game's frames, also at /games/{id}/game/events
+//	GET    /games/{id}/result           the game's result
+//	GET    /games/{id}/log              the game's log as JSON lines
+func (engine *engineServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/games", engine.handleGames)
+	mux.HandleFunc("/games/", engine.handleGame)
+	return cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+	}).Handler(mux)
+}
+
+// Shutdown cancels every running game and waits for them to stop.
+func (engine *engineServer) Shutdown() {
+	engine.mutex.Lock()
+	var running []*engineGame
+	for _, game := range engine.games {
+		if game.Status == GameStatusRunning {
+			game.cancel()
+			running = append(running, game)
+		}
+	}
+	engine.mutex.Unlock()
+
+	for _, game := range running {
+		<-game.done
+	}
+	engine.board.Shutdown()
+}
+
+func (engine *engineServer) handleGames(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		engine.mutex.Lock()
+		statuses := make([]gameStatus, 0, len(engine.games))
+		for _, game := range engine.games {
+			statuses = append(statuses, game.gameStatus)
+		}
+		engine.mutex.Unlock()
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].CreatedAt.Before(statuses[j].CreatedAt) })
+		writeJSON(w, http.StatusOK, statuses)
+	case http.MethodPost:
+		var req createGameRequest
+		body := http.MaxBytesReader(w, r.Body, maxCreateGameRequestSize)
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid game: %w", err))
+			return
+		}
+		status, err := engine.createGame(req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, status)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s isn't allowed", r.Method))
+	}
+}
+
+func (engine *engineServer) handleGame(w http.ResponseWriter, r *http.Request) {
+	id, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/games/"), "/")
+	engine.mutex.Lock()
+	game, ok := engine.games[id]
+	engine.mutex.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("game %q not found", id))
+		return
+	}
+
+	method := http.MethodGet
+	switch {
+	case resource == "start", resource == "cancel":
+		method = http.MethodPost
+	case resource == "" && r.Method == http.MethodDelete:
+		method = http.MethodDelete
+	}
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s isn't allowed", r.Method))
+		return
+	}
+
+	switch resource {
+	case "":
+		if r.Method == http.MethodDelete {
+			engine.deleteGame(game)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, engine.status(game))
+	case "start":
+		if err := engine.startGame(game); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, engine.status(game))
+	case "cancel":
+		if err := engine.cancelGame(game); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, engine.status(game))
+	case "game":
+		engine.board.ServeGame(w, r, id)
+	case "events", "game/events":
+		engine.board.ServeEvents(w, r, id)
+	case "result":
+		status := engine.status(game)
+		if status.Result == nil {
+			writeError(w, http.StatusConflict, fmt.Errorf("game %q is %s and has no result", id, status.Status))
+			return
+		}
+		writeJSON(w, http.StatusOK, status.Result)
+	case "log":
+		if engine.status(game).Status == GameStatusCreated {
+			writeError(w, http.StatusConflict, fmt.Errorf("game %q hasn't started", id))
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		http.ServeFile(w, r, game.logPath)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown resource %q", resource))
+	}
+}
+
+// createGame sets up a game from the request and the template game, ready to be started.
+func (engine *engineServer) createGame(req createGameRequest) (gameStatus, error) {
+	if len(req.Snakes) == 0 {
+		return gameStatus{}, errors.New("a game needs at least one snake")
+	}
+
+	gameState := engine.template
+	gameState.Names, gameState.URLs, gameState.Squads, gameState.SnakeTimeouts = nil, nil, nil, nil
+	squads := make([]string, 0, len(req.Snakes))
+	hasSquads := false
+	for _, snake := range req.Snakes {
+		if _, err := url.ParseRequestURI(snake.URL); err != nil {
+			return gameStatus{}, fmt.Errorf("url %q of snake %q is not valid: %w", snake.URL, snake.Name, err)
+		}
+		gameState.Names = append(gameState.Names, snake.Name)
+		gameState.URLs = append(gameState.URLs, snake.URL)
+		squads = append(squads, snake.Squad)
+		hasSquads = hasSquads || snake.Squad != ""
+	}
+	if req.Width != 0 {
+		gameState.Width, gameState.sizeChanged = req.Width, true
+	}
+	if req.Height != 0 {
+		gameState.Height, gameState.sizeChanged = req.Height, true
+	}
+	if req.Map != "" {
+		gameState.MapName, gameState.MapFile = req.Map, ""
+	}
+	if req.Ruleset != "" {
+		gameState.GameType = req.Ruleset
+	}
+	if hasSquads {
+		if gameState.GameType != rules.GameTypeSquad {
+			return gameStatus{}, fmt.Errorf("squads can only be used with the %q ruleset, got %q", rules.GameTypeSquad, gameState.GameType)
+		}
+		gameState.Squads = squads
+	}
+	if req.Timeout != 0 {
+		gameState.Timeout = req.Timeout
+	}
+	if req.MaxTurns != 0 {
+		gameState.MaxTurns = req.MaxTurns
+	}
+	gameState.Seed = req.Seed
+	gameState.ViewInBrowser = false
+	gameState.Render = RenderNone
+	if err := gameState.Initialize(); err != nil {
+		return gameStatus{}, err
+	}
+
+	id := uuid.New().String()
+	gameState.OutputPath = filepath.Join(engine.logDir, id+".jsonl")
+	if err := engine.board.AddGame(id, gameState.buildBoardGame()); err != nil {
+		return gameStatus{}, err
+	}
+	game := &engineGame{
+		gameStatus: gameStatus{
+			ID:        id,
+			Status:    GameStatusCreated,
+			Seed:      gameState.Seed,
+			CreatedAt: time.Now().UTC(),
+		},
+		gameState: &gameState,
+		logPath:   gameState.OutputPath,
+		done:      make(chan struct{}),
+	}
+
+	engine.mutex.Lock()
+	engine.games[id] = game
+	engine.mutex.Unlock()
+	log.INFO.Printf("Created game %s with seed %d", id, gameState.Seed)
+	engine.removeOldGames()
+	return game.gameStatus, nil
+}
+
+// removeOldGames removes the oldest stopped games once there are more than keepGames of them.
+func (engine *engineServer) removeOldGames() {
+	if engine.keepGames == 0 {
+		return
+	}
+	engine.mutex.Lock()
+	var stopped []*engineGame
+	for _, game := range engine.games {
+		if game.Status != GameStatusCreated && game.Status != GameStatusRunning {
+			stopped = append(stopped, game)
+		}
+	}
+	engine.mutex.Unlock()
+	if len(stopped) <= engine.keepGames {
+		return
+	}
+
+	sort.Slice(stopped, func(i, j int) bool { return stopped[i].CreatedAt.Before(stopped[j].CreatedAt) })
+	for _, game := range stopped[:len(stopped)-engine.keepGames] {
+		engine.removeGame(game)
+	}
+}
+
+// deleteGame cancels a game if it hasn't stopped yet, and then removes it.
+func (engine *engineServer) deleteGame(game *engineGame) {
+	status := engine.status(game).Status
+	if status == GameStatusCreated || status == GameStatusRunning {
+		// The game may stop by itself in the meantime, in which case there's nothing to cancel
+		_ = engine.cancelGame(game)
+	}
+	engine.removeGame(game)
+}
+
+// removeGame stops hosting a stopped game, and removes its events from the board server and its log.
+func (engine *engineServer) removeGame(game *engineGame) {
+	engine.mutex.Lock()
+	delete(engine.games, game.ID)
+	engine.mutex.Unlock()
+
+	engine.board.RemoveGame(game.ID)
+	if err := os.Remove(game.logPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.WARN.Printf("Unable to remove log of game %s: %v", game.ID, err)
+	}
+	log.INFO.Printf("Removed game %s", game.ID)
+}
+
+// startGame runs a game in the background, streaming its frames to the board server.
+func (engine *engineServer) startGame(game *engineGame) error {
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+	if game.Status != GameStatusCreated {
+		return fmt.Errorf("game %q is %s and can't be started", game.ID, game.Status)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	game.Status = GameStatusRunning
+	game.cancel = cancel
+
+	gameState := game.gameState
+	gameState.ctx = ctx
+	if engine.httpClient != nil {
+		gameState.httpClient = engine.httpClient
+	}
+	gameState.sendEvent = func(event board.GameEvent) {
+		if frame, ok := event.Data.(board.GameFrame); ok {
+			engine.mutex.Lock()
+			game.Turn = frame.Turn
+			engine.mutex.Unlock()
+		}
+		engine.board.SendEvent(game.ID, event)
+	}
+
+	go func() {
+		defer close(game.done)
+		defer cancel()
+		err := gameState.Run()
+		engine.board.EndGame(game.ID)
+
+		engine.mutex.Lock()
+		defer engine.mutex.Unlock()
+		switch {
+		case err == nil:
+			game.Status = GameStatusFinished
+			game.Result = gameState.gameResult
+		case ctx.Err() != nil:
+			game.Status = GameStatusCancelled
+			game.Error = err.Error()
+		default:
+			game.Status = GameStatusFailed
+			game.Error = err.Error()
+			log.ERROR.Printf("Game %s failed: %v", game.ID, err)
+		}
+	}()
+	log.INFO.Printf("Started game %s", game.ID)
+	return nil
+}
+
+// cancelGame stops a game. Running games stop before their next turn, and games that haven't started never will.
+func (engine *engineServer) cancelGame(game *engineGame) error {
+	engine.mutex.Lock()
+	switch game.Status {
+	case GameStatusCreated:
+		game.Status = GameStatusCancelled
+		close(game.done)
+		engine.mutex.Unlock()
+		engine.board.EndGame(game.ID)
+	case GameStatusRunning:
+		game.cancel()
+		engine.mutex.Unlock()
+		<-game.done
+	default:
+		engine.mutex.Unlock()
+		return fmt.Errorf("game %q is %s and can't be cancelled", game.ID, game.Status)
+	}
+
+	log.INFO.Printf("Cancelled game %s", game.ID)
+	return nil
+}
+
+func (engine *engineServer) status(game *engineGame) gameStatus {
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+	return game.gameStatus
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.ERROR.Printf("Unable to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	writeJSON(w, statusCode, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
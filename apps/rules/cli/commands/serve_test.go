@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"rules"
+	"rules/board"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// slowHTTPClient waits before every response, so that games take long enough to be cancelled while they run.
+type slowHTTPClient struct {
+	stubHTTPClient
+}
+
+func (client slowHTTPClient) Post(url string, contentType string, body io.Reader) (*http.Response, time.Duration, error) {
+	time.Sleep(client.latency)
+	return client.stubHTTPClient.Post(url, contentType, body)
+}
+
+func (client slowHTTPClient) WithTimeout(timeout time.Duration) TimedHttpClient {
+	return client
+}
+
+func startTestEngine(t *testing.T, latency time.Duration) (*engineServer, string) {
+	template := *buildDefaultGameState()
+	engine := newEngineServer(template, t.TempDir())
+	engine.httpClient = slowHTTPClient{stubHTTPClient{nil, 200, func(_ string) string { return `{"move": "down"}` }, latency}}
+	server := httptest.NewServer(engine.Handler())
+	t.Cleanup(func() {
+		engine.Shutdown()
+		server.Close()
+	})
+	return engine, server.URL
+}
+
+// callEngine sends a request to the engine API and decodes the JSON response into value, if given.
+func callEngine(t *testing.T, method, url, body string, value interface{}) int {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	if value != nil {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(value))
+	}
+	return res.StatusCode
+}
+
+func createTestGame(t *testing.T, serverURL string, body string) gameStatus {
+	var status gameStatus
+	require.Equal(t, http.StatusCreated, callEngine(t, http.MethodPost, serverURL+"/games", body, &status))
+	return status
+}
+
+func waitForGame(t *testing.T, serverURL, id string) gameStatus {
+	var status gameStatus
+	require.Eventually(t, func() bool {
+		status = gameStatus{}
+		callEngine(t, http.MethodGet, serverURL+"/games/"+id, "", &status)
+		return status.Status != GameStatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return status
+}
+
+const testGameRequest = `{
+	"snakes": [{"name": "one", "url": "http://one.example.com"}, {"name": "two", "url": "http://two.example.com"}],
+	"width": 7, "height": 7, "map": "standard", "ruleset": "standard", "seed": 3, "maxTurns": 5
+}`
+
+// testLongGameRequest is a game on a large board, where snakes that only move down take several turns to lose
+const testLongGameRequest = `{
+	"snakes": [{"name": "one", "url": "http://one.example.com"}, {"name": "two", "url": "http://two.example.com"}],
+	"width": 19, "height": 19
+}`
+
+func TestEngineRunsGame(t *testing.T) {
+	_, serverURL := startTestEngine(t, time.Millisecond)
+
+	created := createTestGame(t, serverURL, testGameRequest)
+	require.Equal(t, GameStatusCreated, created.Status)
+	require.Equal(t, int64(3), created.Seed)
+
+	var info struct {
+		Game board.Game
+	}
+	require.Equal(t, http.StatusOK, callEngine(t, http.MethodGet, serverURL+"/games/"+created.ID+"/game", "", &info))
+	require.Equal(t, 7, info.Game.Width)
+	require.Equal(t, "standard", info.Game.Map)
+
+	// Frames are streamed at the same paths as the board server, relative to the game
+	ws, _, err := websocket.DefaultDialer.Dial(strings.Replace(serverURL, "http", "ws", 1)+"/games/"+created.ID+"/game/events", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var started gameStatus
+	require.Equal(t, http.StatusAccepted, callEngine(t, http.MethodPost, serverURL+"/games/"+created.ID+"/start", "", &started))
+	require.Equal(t, GameStatusRunning, started.Status)
+
+	eventTypes := []board.GameEventType{}
+	for {
+		_, message, err := ws.ReadMessage()
+		if err != nil {
+			break
+		}
+		var event board.GameEvent
+		require.NoError(t, json.Unmarshal(message, &event))
+		eventTypes = append(eventTypes, event.EventType)
+	}
+	require.Greater(t, len(eventTypes), 1)
+	require.Equal(t, board.EVENT_TYPE_GAME_END, eventTypes[len(eventTypes)-1])
+
+	finished := waitForGame(t, serverURL, created.ID)
+	require.Equal(t, GameStatusFinished, finished.Status)
+	require.Equal(t, len(eventTypes)-2, finished.Turn)
+	require.NotNil(t, finished.Result)
+	require.Len(t, finished.Result.Placements, 2)
+
+	var res result
+	require.Equal(t, http.StatusOK, callEngine(t, http.MethodGet, serverURL+"/games/"+created.ID+"/result", "", &res))
+	require.Equal(t, *finished.Result, res)
+
+	logRes, err := http.Get(serverURL + "/games/" + created.ID + "/log")
+	require.NoError(t, err)
+	defer logRes.Body.Close()
+	require.Equal(t, http.StatusOK, logRes.StatusCode)
+	require.Equal(t, "application/x-ndjson", logRes.Header.Get("Content-Type"))
+	contents, err := io.ReadAll(logRes.Body)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	require.Len(t, lines, finished.Turn+3)
+	require.Contains(t, lines[len(lines)-1], `"placements":[`)
+
+	var errorRes struct{ Error string }
+	require.Equal(t, http.StatusConflict, callEngine(t, http.MethodPost, serverURL+"/games/"+created.ID+"/start", "", &errorRes))
+	require.Equal(t, `game "`+created.ID+`" is finished and can't be started`, errorRes.Error)
+
+	var games []gameStatus
+	require.Equal(t, http.StatusOK, callEngine(t, http.MethodGet, serverURL+"/games", "", &games))
+	require.Len(t, games, 1)
+	require.Equal(t, created.ID, games[0].ID)
+}
+
+func TestEngineCancelsGames(t *testing.T) {
+	_, serverURL := startTestEngine(t, 20*time.Millisecond)
+
+	running := createTestGame(t, serverURL, testLongGameRequest)
+	require.Equal(t, http.StatusAccepted, callEngine(t, http.MethodPost, serverURL+"/games/"+running.ID+"/start", "", nil))
+	var cancelled gameStatus
+	require.Equal(t, http.StatusOK, callEngine(t, http.MethodPost, serverURL+"/games/"+running.ID+"/cancel", "", &cancelled))
+	require.Equal(t, GameStatusCancelled, cancelled.Status)
+	require.Contains(t, cancelled.Error, "context canceled")
+	require.Nil(t, cancelled.Result)
+
+	var errorRes struct{ Error string }
+	require.Equal(t, http.StatusConflict, callEngine(t, http.MethodGet, serverURL+"/games/"+running.ID+"/result", "", &errorRes))
+	require.Equal(t, `game "`+running.ID+`" is cancelled and has no result`, errorRes.Error)
+	require.Equal(t, http.StatusConflict, callEngine(t, http.MethodPost, serverURL+"/games/"+running.ID+"/cancel", "", &errorRes))
+
+	// Games can be cancelled before they start
+	created := createTestGame(t, serverURL, testGameRequest)
+	require.Equal(t, http.StatusOK, callEngine(t, http.MethodPost, serverURL+"/games/"+created.ID+"/cancel", "", &cancelled))
+	require.Equal(t, GameStatusCancelled, cancelled.Status)
+	require.Equal(t, http.StatusConflict, callEngine(t, http.MethodPost, serverURL+"/games/"+created.ID+"/start", "", nil))
+}
+
+func TestEngineShutdownCancelsGames(t *testing.T) {
+	engine, serverURL := startTestEngine(t, 20*time.Millisecond)
+	created := createTestGame(t, serverURL, testLongGameRequest)
+	require.Equal(t, http.StatusAccepted, callEngine(t, http.MethodPost, serverURL+"/games/"+created.ID+"/start", "", nil))
+
+	engine.Shutdown()
+	require.Equal(t, GameStatusCancelled, engine.status(engine.games[created.ID]).Status)
+}
+
+func TestEngineDeletesGames(t *testing.T) {
+	engine, serverURL := startTestEngine(t, 20*time.Millisecond)
+
+	finished := createTestGame(t, serverURL, testGameRequest)
+	require.Equal(t, http.StatusAccepted, callEngine(t, http.MethodPost, serverURL+"/games/"+finished.ID+"/start", "", nil))
+	waitForGame(t, serverURL, finished.ID)
+	logPath := engine.games[finished.ID].logPath
+	require.FileExists(t, logPath)
+
+	require.Equal(t, http.StatusNoContent, callEngine(t, http.MethodDelete, serverURL+"/games/"+finished.ID, "", nil))
+	require.Equal(t, http.StatusNotFound, callEngine(t, http.MethodGet, serverURL+"/games/"+finished.ID, "", nil))
+	require.NoFileExists(t, logPath)
+
+	// The game's events are removed from the board server too
+	res := httptest.NewRecorder()
+	engine.board.ServeGame(res, httptest.NewRequest(http.MethodGet, "/", nil), finished.ID)
+	require.Equal(t, http.StatusNotFound, res.Code)
+
+	// Running games are cancelled before they are removed
+	running := createTestGame(t, serverURL, testLongGameRequest)
+	require.Equal(t, http.StatusAccepted, callEngine(t, http.MethodPost, serverURL+"/games/"+running.ID+"/start", "", nil))
+	game := engine.games[running.ID]
+	require.Equal(t, http.StatusNoContent, callEngine(t, http.MethodDelete, serverURL+"/games/"+running.ID, "", nil))
+	require.Equal(t, GameStatusCancelled, engine.status(game).Status)
+	require.Empty(t, engine.games)
+}
+
+func TestEngineRemovesOldGames(t *testing.T) {
+	engine, serverURL := startTestEngine(t, time.Millisecond)
+	engine.keepGames = 1
+
+	first := createTestGame(t, serverURL, testGameRequest)
+	require.Equal(t, http.StatusOK, callEngine(t, http.MethodPost, serverURL+"/games/"+first.ID+"/cancel", "", nil))
+	second := createTestGame(t, serverURL, testGameRequest)
+	require.Equal(t, http.StatusOK, callEngine(t, http.MethodPost, serverURL+"/games/"+second.ID+"/cancel", "", nil))
+
+	// Creating a game removes the oldest stopped games beyond the limit, but not games waiting to start
+	third := createTestGame(t, serverURL, testGameRequest)
+	var games []gameStatus
+	require.Equal(t, http.StatusOK, callEngine(t, http.MethodGet, serverURL+"/games", "", &games))
+	require.Len(t, games, 2)
+	require.Equal(t, second.ID, games[0].ID)
+	require.Equal(t, third.ID, games[1].ID)
+}
+
+func TestEngineErrors(t *testing.T) {
+	_, serverURL := startTestEngine(t, time.Millisecond)
+	created := createTestGame(t, serverURL, testGameRequest)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		statusCode int
+		err        string
+	}{
+		{"invalid JSON", http.MethodPost, "/games", `{"snakes": `, http.StatusBadRequest, "invalid game: unexpected EOF"},
+		{"no snakes", http.MethodPost, "/games", `{"snakes": []}`, http.StatusBadRequest, "a game needs at least one snake"},
+		{"invalid URL", http.MethodPost, "/games", `{"snakes": [{"name": "one", "url": "one"}]}`, http.StatusBadRequest, `url "one" of snake "one" is not valid: parse "one": invalid URI for request`},
+		{"unknown map", http.MethodPost, "/games", `{"snakes": [{"name": "one", "url": "http://one"}], "map": "moon"}`, http.StatusBadRequest, `unknown map "moon": map not found`},
+		{"invalid timeout", http.MethodPost, "/games", `{"snakes": [{"name": "one", "url": "http://one"}], "timeout": -1}`, http.StatusBadRequest, "timeout must be greater than 0, got -1"},
+		{"squad outside squad games", http.MethodPost, "/games", `{"snakes": [{"name": "one", "url": "http://one", "squad": "red"}], "ruleset": "standard"}`, http.StatusBadRequest, `squads can only be used with the "squad" ruleset, got "standard"`},
+		{"request too large", http.MethodPost, "/games", `{"snakes": [], "map": "` + strings.Repeat("x", maxCreateGameRequestSize) + `"}`, http.StatusBadRequest, "invalid game: http: request body too large"},
+		{"games method", http.MethodDelete, "/games", "", http.StatusMethodNotAllowed, "method DELETE isn't allowed"},
+		{"unknown game", http.MethodGet, "/games/nope", "", http.StatusNotFound, `game "nope" not found`},
+		{"start method", http.MethodGet, "/games/" + created.ID + "/start", "", http.StatusMethodNotAllowed, "method GET isn't allowed"},
+		{"status method", http.MethodPost, "/games/" + created.ID, "", http.StatusMethodNotAllowed, "method POST isn't allowed"},
+		{"delete resource", http.MethodDelete, "/games/" + created.ID + "/log", "", http.StatusMethodNotAllowed, "method DELETE isn't allowed"},
+		{"no result", http.MethodGet, "/games/" + created.ID + "/result", "", http.StatusConflict, `game "` + created.ID + `" is created and has no result`},
+		{"no log", http.MethodGet, "/games/" + created.ID + "/log", "", http.StatusConflict, `game "` + created.ID + `" hasn't started`},
+		{"unknown resource", http.MethodGet, "/games/" + created.ID + "/moves", "", http.StatusNotFound, `unknown resource "moves"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var errorRes struct{ Error string }
+			require.Equal(t, test.statusCode, callEngine(t, test.method, serverURL+test.path, test.body, &errorRes))
+			require.Equal(t, test.err, errorRes.Error)
+		})
+	}
+}
+
+func TestEngineUsesTemplate(t *testing.T) {
+	template := *buildDefaultGameState()
+	template.Width, template.Height, template.Timeout = 9, 13, 250
+	template.Names, template.URLs = []string{"flag"}, []string{"http://flag.example.com"}
+	logDir := t.TempDir()
+	engine := newEngineServer(template, logDir)
+
+	status, err := engine.createGame(createGameRequest{
+		Snakes:  []createGameSnake{{Name: "one", URL: "http://one.example.com", Squad: "red"}},
+		Height:  7,
+		Ruleset: rules.GameTypeSquad,
+	})
+	require.NoError(t, err)
+	gameState := engine.games[status.ID].gameState
+	require.Equal(t, 9, gameState.Width)
+	require.Equal(t, 7, gameState.Height)
+	require.Equal(t, 250, gameState.Timeout)
+	require.Equal(t, []string{"one"}, gameState.Names)
+	require.Equal(t, []string{"red"}, gameState.Squads)
+	require.NotZero(t, status.Seed)
+	require.Equal(t, filepath.Join(logDir, status.ID+".jsonl"), gameState.OutputPath)
+}
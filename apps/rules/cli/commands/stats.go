@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"rules"
+)
+
+// SnakeStats summarises how a snake played over a whole game.
+type SnakeStats struct {
+	FinalLength      int   `json:"finalLength"`
+	MaxLength        int   `json:"maxLength"`
+	FoodEaten        int   `json:"foodEaten"`
+	Kills            int   `json:"kills"`
+	TurnsSurvived    int   `json:"turnsSurvived"`
+	AverageLatencyMS int64 `json:"averageLatencyMs"`
+	MaxLatencyMS     int64 `json:"maxLatencyMs"`
+	Timeouts         int   `json:"timeouts"`
+	InvalidMoves     int   `json:"invalidMoves"`
+
+	totalLatency time.Duration
+	numRequests  int
+	latencies    []time.Duration
+	moves        []string
+}
+
+// gameStats collects SnakeStats turn by turn while a game is played.
+type gameStats map[string]*SnakeStats
+
+func newGameStats(boardState *rules.BoardState) gameStats {
+	stats := gameStats{}
+	for _, snake := range boardState.Snakes {
+		stats[snake.ID] = &SnakeStats{MaxLength: len(snake.Body)}
+	}
+	return stats
+}
+
+// recordTurn updates the stats with the responses snakes gave to move from prevState to nextState.
+func (stats gameStats) recordTurn(prevState, nextState *rules.BoardState, snakeStates map[string]SnakeState) {
+	// The game over stages run first, so the moves requested on the last turn are thrown away and
+	// the turn doesn't change. Those requests aren't part of the game and aren't counted.
+	if nextState.Turn == prevState.Turn {
+		return
+	}
+
+	food := map[rules.Point]bool{}
+	for _, p := range prevState.Food {
+		food[p] = true
+	}
+
+	// Only snakes that were alive at the start of the turn were asked for a move
+	for _, snake := range prevState.Snakes {
+		s, ok := stats[snake.ID]
+		if !ok || snake.EliminatedCause != rules.NotEliminated {
+			continue
+		}
+		snakeState := snakeStates[snake.ID]
+
+		s.numRequests++
+		s.totalLatency += snakeState.Latency
+		s.latencies = append(s.latencies, snakeState.Latency)
+		if latencyMS := snakeState.Latency.Milliseconds(); latencyMS > s.MaxLatencyMS {
+			s.MaxLatencyMS = latencyMS
+		}
+		s.moves = append(s.moves, snakeState.LastMove)
+		if isTimeout(snakeState.Error) {
+			s.Timeouts++
+		} else if snakeState.Error != nil || snakeState.StatusCode != 200 || snakeState.InvalidMove {
+			s.InvalidMoves++
+		}
+	}
+
+	for _, snake := range nextState.Snakes {
+		s, ok := stats[snake.ID]
+		if !ok || snake.EliminatedCause != rules.NotEliminated || len(snake.Body) == 0 {
+			continue
+		}
+		if food[snake.Body[0]] {
+			s.FoodEaten++
+		}
+		if len(snake.Body) > s.MaxLength {
+			s.MaxLength = len(snake.Body)
+		}
+	}
+}
+
+// finish fills in the stats that are taken from the final board state.
+func (stats gameStats) finish(boardState *rules.BoardState) {
+	for _, snake := range boardState.Snakes {
+		s, ok := stats[snake.ID]
+		if !ok {
+			continue
+		}
+		s.FinalLength = len(snake.Body)
+		if snake.EliminatedCause == rules.NotEliminated {
+			s.TurnsSurvived = boardState.Turn
+		} else {
+			s.TurnsSurvived = snake.EliminatedOnTurn - 1
+			if killer, ok := stats[snake.EliminatedBy]; ok && snake.EliminatedBy != snake.ID {
+				killer.Kills++
+			}
+		}
+		if s.numRequests > 0 {
+			s.AverageLatencyMS = (s.totalLatency / time.Duration(s.numRequests)).Milliseconds()
+		}
+	}
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"rules"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameStats(t *testing.T) {
+	turn0 := rules.NewBoardState(11, 11).
+		WithFood([]rules.Point{{X: 1, Y: 3}}).
+		WithSnakes([]rules.Snake{
+			{ID: "eater", Body: []rules.Point{{X: 1, Y: 2}, {X: 1, Y: 1}, {X: 1, Y: 0}}},
+			{ID: "killer", Body: []rules.Point{{X: 5, Y: 2}, {X: 5, Y: 1}, {X: 5, Y: 0}}},
+			{ID: "victim", Body: []rules.Point{{X: 6, Y: 2}, {X: 6, Y: 1}, {X: 6, Y: 0}}},
+		})
+	stats := newGameStats(turn0)
+
+	turn1 := turn0.Clone()
+	turn1.Turn = 1
+	turn1.Food = []rules.Point{}
+	turn1.Snakes[0].Body = []rules.Point{{X: 1, Y: 3}, {X: 1, Y: 2}, {X: 1, Y: 1}, {X: 1, Y: 1}}
+	turn1.Snakes[1].Body = []rules.Point{{X: 5, Y: 3}, {X: 5, Y: 2}, {X: 5, Y: 1}}
+	turn1.Snakes[2].Body = []rules.Point{{X: 5, Y: 2}, {X: 6, Y: 2}, {X: 6, Y: 1}}
+	turn1.Snakes[2].EliminatedCause = rules.EliminatedByCollision
+	turn1.Snakes[2].EliminatedBy = "killer"
+	turn1.Snakes[2].EliminatedOnTurn = 1
+	stats.recordTurn(turn0, turn1, map[string]SnakeState{
+		"eater":  {ID: "eater", LastMove: "up", StatusCode: 200, Latency: 100 * time.Millisecond},
+		"killer": {ID: "killer", LastMove: "up", StatusCode: 200, Latency: 20 * time.Millisecond, InvalidMove: true},
+		"victim": {ID: "victim", LastMove: "left", Latency: 500 * time.Millisecond, Error: &url.Error{Op: "Post", URL: "http://example.com", Err: os.ErrDeadlineExceeded}},
+	})
+
+	turn2 := turn1.Clone()
+	turn2.Turn = 2
+	turn2.Snakes[0].Body = []rules.Point{{X: 1, Y: 4}, {X: 1, Y: 3}, {X: 1, Y: 2}, {X: 1, Y: 1}}
+	turn2.Snakes[1].Body = []rules.Point{{X: 5, Y: 4}, {X: 5, Y: 3}, {X: 5, Y: 2}}
+	stats.recordTurn(turn1, turn2, map[string]SnakeState{
+		"eater":  {ID: "eater", LastMove: "up", StatusCode: 200, Latency: 300 * time.Millisecond},
+		"killer": {ID: "killer", LastMove: "up", Error: errors.New("connection refused")},
+	})
+
+	// The moves requested when the game ends are thrown away, so they aren't counted
+	stats.recordTurn(turn2, turn2.Clone(), map[string]SnakeState{
+		"eater":  {ID: "eater", LastMove: "up", Latency: 900 * time.Millisecond, Error: &url.Error{Op: "Post", URL: "http://example.com", Err: os.ErrDeadlineExceeded}},
+		"killer": {ID: "killer", LastMove: "up", StatusCode: 200, Latency: 50 * time.Millisecond, InvalidMove: true},
+	})
+	stats.finish(turn2)
+
+	require.Equal(t, SnakeStats{
+		FinalLength: 4, MaxLength: 4, FoodEaten: 1, TurnsSurvived: 2, AverageLatencyMS: 200, MaxLatencyMS: 300,
+		totalLatency: 400 * time.Millisecond, numRequests: 2, latencies: []time.Duration{100 * time.Millisecond, 300 * time.Millisecond}, moves: []string{"up", "up"},
+	}, *stats["eater"])
+	require.Equal(t, SnakeStats{
+		FinalLength: 3, MaxLength: 3, Kills: 1, TurnsSurvived: 2, AverageLatencyMS: 10, MaxLatencyMS: 20, InvalidMoves: 2,
+		totalLatency: 20 * time.Millisecond, numRequests: 2, latencies: []time.Duration{20 * time.Millisecond, 0}, moves: []string{"up", "up"},
+	}, *stats["killer"])
+	require.Equal(t, SnakeStats{
+		FinalLength: 3, MaxLength: 3, TurnsSurvived: 0, AverageLatencyMS: 500, MaxLatencyMS: 500, Timeouts: 1,
+		totalLatency: 500 * time.Millisecond, numRequests: 1, latencies: []time.Duration{500 * time.Millisecond}, moves: []string{"left"},
+	}, *stats["victim"])
+}
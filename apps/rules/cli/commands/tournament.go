@@ -0,0 +1,619 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	log "github.com/spf13/jwalterweatherman"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatRoundRobin        = "round-robin"
+	FormatSingleElimination = "single-elimination"
+	FormatDoubleElimination = "double-elimination"
+)
+
+// Participant is a snake entered into a tournament.
+type Participant struct {
+	Name   string `json:"name" yaml:"name"`
+	URL    string `json:"url" yaml:"url"`
+	Author string `json:"author,omitempty" yaml:"author"`
+}
+
+type participantsFile struct {
+	Participants []Participant `json:"participants" yaml:"participants"`
+}
+
+type tournamentOptions struct {
+	ParticipantsPath string
+	Format           string
+	Groups           int
+	GroupSize        int
+	Games            int
+	Points           []int // points for each place in a game, places past the end of the list score nothing
+	Parallel         int
+	OutputDir        string
+}
+
+// tournamentGame is a game in a tournament and, once it has been played, its result.
+type tournamentGame struct {
+	ID         string      `json:"id"`
+	Stage      string      `json:"stage"`
+	Snakes     []string    `json:"snakes"`
+	Seed       int64       `json:"seed"`
+	LogPath    string      `json:"logPath,omitempty"`
+	WinnerName string      `json:"winnerName,omitempty"`
+	IsDraw     bool        `json:"isDraw"`
+	Placements []Placement `json:"placements"`
+
+	order [3]int // round, match and game number, used to list games in the order they were played
+}
+
+// Standing is where a participant finished a tournament, with totals from every game they played.
+type Standing struct {
+	Place        int    `json:"place"`
+	Name         string `json:"name"`
+	Author       string `json:"author,omitempty"`
+	Group        string `json:"group,omitempty"`
+	EliminatedIn string `json:"eliminatedIn,omitempty"`
+	Points       int    `json:"points"`
+	Games        int    `json:"games"`
+	Wins         int    `json:"wins"`
+	Draws        int    `json:"draws"`
+	Losses       int    `json:"losses"`
+	Kills        int    `json:"kills"`
+}
+
+// tournament plays every game of a tournament and collects their results.
+type tournament struct {
+	opts         tournamentOptions
+	template     GameState
+	participants []Participant
+	seeds        map[string]int // position of each participant in the participants file
+
+	// playGame plays a single game and records its result, it is replaced in tests
+	playGame   func(*tournamentGame) error
+	httpClient TimedHttpClient
+
+	mutex     sync.Mutex
+	games     []*tournamentGame
+	semaphore chan struct{} // limits the number of games played at once
+}
+
+func NewTournamentCommand() *cobra.Command {
+	opts := tournamentOptions{}
+	gameState := &GameState{}
+
+	var tournamentCmd = &cobra.Command{
+		Use:   "tournament",
+		Short: "Run a tournament between a list of snakes.",
+		Long:  "Run a round-robin or elimination tournament between the snakes in a participants file, writing the standings and a log of every game.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			participants, err := loadParticipants(opts.ParticipantsPath)
+			if err != nil {
+				return err
+			}
+			gameState.sizeChanged = boardSizeChanged(cmd)
+			if gameState.Seed == 0 {
+				gameState.Seed = time.Now().UTC().UnixNano()
+			}
+
+			t, err := newTournament(participants, *gameState, opts)
+			if err != nil {
+				return err
+			}
+			log.INFO.Printf("Starting %s tournament with %d participants and seed %d", opts.Format, len(participants), gameState.Seed)
+			standings, err := t.run()
+			if err != nil {
+				return err
+			}
+			if err := t.writeResults(standings); err != nil {
+				return err
+			}
+			return printStandings(cmd.OutOrStdout(), standings)
+		},
+	}
+
+	tournamentCmd.Flags().StringVarP(&opts.ParticipantsPath, "participants", "p", "", "Path to a YAML or JSON file listing the participants' names and URLs, in seeding order")
+	tournamentCmd.Flags().StringVarP(&opts.Format, "format", "f", FormatRoundRobin, "Tournament format: round-robin, single-elimination or double-elimination")
+	tournamentCmd.Flags().IntVar(&opts.Groups, "groups", 1, "Number of groups to split the participants into for round-robin")
+	tournamentCmd.Flags().IntVar(&opts.GroupSize, "group-size", 2, "Number of snakes in each round-robin game, every combination of snakes in a group is played (0 for the whole group)")
+	tournamentCmd.Flags().IntVar(&opts.Games, "games", 1, "Number of games played by each combination of snakes in round-robin, or the best of this many games in elimination matches")
+	tournamentCmd.Flags().IntSliceVar(&opts.Points, "points", []int{1}, "Points awarded for first place, second place and so on in each game, places past the last one score nothing")
+	tournamentCmd.Flags().IntVar(&opts.Parallel, "parallel", 1, "Number of games to play at the same time")
+	tournamentCmd.Flags().StringVarP(&opts.OutputDir, "output", "o", "tournament", "Directory to write the standings and game logs to")
+	_ = tournamentCmd.MarkFlagRequired("participants")
+
+	addGameFlags(tournamentCmd, gameState)
+
+	tournamentCmd.Flags().SortFlags = false
+
+	return tournamentCmd
+}
+
+func loadParticipants(path string) ([]Participant, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file participantsFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported participants file extension %q, expected .yaml, .yml or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse participants file %s: %w", path, err)
+	}
+	return file.Participants, nil
+}
+
+func newTournament(participants []Participant, template GameState, opts tournamentOptions) (*tournament, error) {
+	if len(participants) < 2 {
+		return nil, fmt.Errorf("a tournament needs at least 2 participants, got %d", len(participants))
+	}
+	seeds := map[string]int{}
+	for i, participant := range participants {
+		if participant.Name == "" || participant.URL == "" {
+			return nil, fmt.Errorf("participant %d needs a name and a URL", i+1)
+		}
+		if _, ok := seeds[participant.Name]; ok {
+			return nil, fmt.Errorf("participant names must be unique, %q is used more than once", participant.Name)
+		}
+		seeds[participant.Name] = i
+	}
+
+	switch opts.Format {
+	case FormatRoundRobin:
+		if opts.Groups < 1 {
+			return nil, fmt.Errorf("groups must be at least 1, got %d", opts.Groups)
+		}
+		if len(participants) < opts.Groups*2 {
+			return nil, fmt.Errorf("%d participants can't be split into %d groups of at least 2", len(participants), opts.Groups)
+		}
+		if opts.GroupSize != 0 && opts.GroupSize < 2 {
+			return nil, fmt.Errorf("group size must be at least 2, got %d", opts.GroupSize)
+		}
+	case FormatSingleElimination, FormatDoubleElimination:
+	default:
+		return nil, fmt.Errorf("unknown tournament format %q", opts.Format)
+	}
+	if opts.Games < 1 {
+		return nil, fmt.Errorf("games must be at least 1, got %d", opts.Games)
+	}
+	if opts.Parallel < 1 {
+		return nil, fmt.Errorf("parallel must be at least 1, got %d", opts.Parallel)
+	}
+
+	t := &tournament{
+		opts:         opts,
+		template:     template,
+		participants: participants,
+		seeds:        seeds,
+		semaphore:    make(chan struct{}, opts.Parallel),
+	}
+	t.playGame = t.runGame
+	return t, nil
+}
+
+func (t *tournament) run() ([]Standing, error) {
+	switch t.opts.Format {
+	case FormatSingleElimination:
+		return t.runElimination(1)
+	case FormatDoubleElimination:
+		return t.runElimination(2)
+	}
+	return t.runRoundRobin()
+}
+
+// newGame creates a game between the named snakes. Game seeds are taken from the tournament seed and the game ID,
+// so running a tournament again with the same seed plays the same games.
+func (t *tournament) newGame(id string, stage string, snakes []string, order [3]int) *tournamentGame {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	game := &tournamentGame{
+		ID:     id,
+		Stage:  stage,
+		Snakes: snakes,
+		Seed:   t.template.Seed + int64(h.Sum64()>>1),
+		order:  order,
+	}
+	if t.opts.OutputDir != "" {
+		game.LogPath = filepath.Join(t.opts.OutputDir, "games", id+".jsonl")
+	}
+
+	t.mutex.Lock()
+	t.games = append(t.games, game)
+	t.mutex.Unlock()
+	return game
+}
+
+// play plays a game once one of the parallel slots is free.
+func (t *tournament) play(game *tournamentGame) error {
+	t.semaphore <- struct{}{}
+	defer func() { <-t.semaphore }()
+
+	log.INFO.Printf("Playing %s (%s): %s", game.ID, game.Stage, strings.Join(game.Snakes, ", "))
+	if err := t.playGame(game); err != nil {
+		return fmt.Errorf("error playing %s: %w", game.ID, err)
+	}
+	if game.IsDraw {
+		log.INFO.Printf("Finished %s: draw", game.ID)
+	} else {
+		log.INFO.Printf("Finished %s: %s won", game.ID, game.WinnerName)
+	}
+	return nil
+}
+
+// runGame plays a tournament game between its snakes and records the winner and placements on it.
+func (t *tournament) runGame(game *tournamentGame) error {
+	urls := make([]string, 0, len(game.Snakes))
+	for _, name := range game.Snakes {
+		urls = append(urls, t.participants[t.seeds[name]].URL)
+	}
+	res, err := runHeadlessGame(t.template, game.Snakes, urls, game.Seed, game.LogPath, t.httpClient)
+	if err != nil {
+		return err
+	}
+
+	game.WinnerName = res.WinnerName
+	game.IsDraw = res.IsDraw
+	game.Placements = res.Placements
+	return nil
+}
+
+// playAll plays games in parallel, up to the parallel limit, and stops starting new games after the first error.
+func (t *tournament) playAll(games []*tournamentGame) error {
+	i := 0
+	return runParallel(context.Background(), t.opts.Parallel, func() (func(context.Context) error, error) {
+		if i == len(games) {
+			return nil, nil
+		}
+		game := games[i]
+		i++
+		return func(context.Context) error {
+			return t.play(game)
+		}, nil
+	})
+}
+
+// runRoundRobin splits the participants into groups, and plays every combination of GroupSize snakes in each group.
+func (t *tournament) runRoundRobin() ([]Standing, error) {
+	groups := splitGroups(t.participants, t.opts.Groups)
+
+	groupOf := map[string]string{}
+	var games []*tournamentGame
+	for i, group := range groups {
+		groupName := string(rune('A' + i%26))
+		for _, participant := range group {
+			groupOf[participant.Name] = groupName
+		}
+
+		size := t.opts.GroupSize
+		if size == 0 || size > len(group) {
+			size = len(group)
+		}
+		for c, combination := range combinations(len(group), size) {
+			snakes := make([]string, 0, size)
+			for _, j := range combination {
+				snakes = append(snakes, group[j].Name)
+			}
+			for g := 0; g < t.opts.Games; g++ {
+				id := fmt.Sprintf("group-%s-%d-%d", strings.ToLower(groupName), c+1, g+1)
+				games = append(games, t.newGame(id, "group "+groupName, snakes, [3]int{0, len(games), 0}))
+			}
+		}
+	}
+
+	if err := t.playAll(games); err != nil {
+		return nil, err
+	}
+
+	standings := t.tally(games)
+	for i := range standings {
+		standings[i].Group = groupOf[standings[i].Name]
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.Kills != b.Kills {
+			return a.Kills > b.Kills
+		}
+		return t.seeds[a.Name] < t.seeds[b.Name]
+	})
+
+	// Places are within each group, and participants with the same points, wins and kills share a place
+	groupStart := 0
+	for i := range standings {
+		if i > 0 && standings[i].Group != standings[i-1].Group {
+			groupStart = i
+		}
+		standings[i].Place = i - groupStart + 1
+		if i > groupStart {
+			prev := standings[i-1]
+			if standings[i].Points == prev.Points && standings[i].Wins == prev.Wins && standings[i].Kills == prev.Kills {
+				standings[i].Place = prev.Place
+			}
+		}
+	}
+	return standings, nil
+}
+
+// match is a series of games between two snakes in an elimination round.
+type match struct {
+	round  int
+	index  int
+	id     string
+	stage  string
+	snakes [2]string
+	winner string
+	loser  string
+}
+
+// runElimination plays an elimination tournament where participants are knocked out after maxLosses matches.
+// Snakes are reseeded every round, so the highest seeds play the lowest, and when the number of snakes in a bracket
+// isn't a power of two the highest seeds get a bye. With two losses, snakes that lose a match move to a losers
+// bracket, and the last snake from each bracket meet in the final.
+func (t *tournament) runElimination(maxLosses int) ([]Standing, error) {
+	losses := map[string]int{}
+	eliminatedIn := map[string]string{}
+	eliminatedRound := map[string]int{}
+
+	for round := 1; ; round++ {
+		brackets := make([][]string, maxLosses)
+		alive := 0
+		for _, participant := range t.participants {
+			if l := losses[participant.Name]; l < maxLosses {
+				brackets[l] = append(brackets[l], participant.Name)
+				alive++
+			}
+		}
+		if alive == 1 {
+			break
+		}
+
+		var matches []*match
+		if alive == 2 {
+			finalists := append(append([]string{}, brackets[0]...), brackets[len(brackets)-1]...)
+			matches = append(matches, &match{round: round, id: fmt.Sprintf("round-%d-final", round), stage: "final", snakes: [2]string{finalists[0], finalists[1]}})
+		} else {
+			for l, bracket := range brackets {
+				prefix, stage := "round", fmt.Sprintf("round %d", round)
+				if l > 0 {
+					prefix, stage = "losers-round", fmt.Sprintf("losers round %d", round)
+				}
+				for i, pair := range pairBySeed(bracket) {
+					matches = append(matches, &match{round: round, index: len(matches), id: fmt.Sprintf("%s-%d-match-%d", prefix, round, i+1), stage: stage, snakes: pair})
+				}
+			}
+		}
+
+		errs := make(chan error, len(matches))
+		var wg sync.WaitGroup
+		for _, m := range matches {
+			wg.Add(1)
+			go func(m *match) {
+				defer wg.Done()
+				errs <- t.playMatch(m)
+			}(m)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, m := range matches {
+			losses[m.loser]++
+			if losses[m.loser] == maxLosses {
+				eliminatedIn[m.loser] = m.stage
+				eliminatedRound[m.loser] = round
+			}
+		}
+	}
+
+	standings := t.tally(t.games)
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if eliminatedRound[a.Name] != eliminatedRound[b.Name] {
+			// The winner was never eliminated, so sorts first
+			return eliminatedRound[a.Name] == 0 || (eliminatedRound[b.Name] != 0 && eliminatedRound[a.Name] > eliminatedRound[b.Name])
+		}
+		return t.seeds[a.Name] < t.seeds[b.Name]
+	})
+	for i := range standings {
+		standings[i].Place = i + 1
+		standings[i].EliminatedIn = eliminatedIn[standings[i].Name]
+		// Snakes knocked out in the same round share a place
+		if i > 0 && eliminatedRound[standings[i].Name] == eliminatedRound[standings[i-1].Name] {
+			standings[i].Place = standings[i-1].Place
+		}
+	}
+	return standings, nil
+}
+
+// playMatch plays the best of Games games between two snakes. If neither snake has won more games by then, up to
+// as many again are played until one of them wins a game, and if it is still tied the higher seed goes through.
+func (t *tournament) playMatch(m *match) error {
+	wins := map[string]int{}
+	needed := t.opts.Games/2 + 1
+	for g := 1; g <= t.opts.Games*2; g++ {
+		if g > t.opts.Games && wins[m.snakes[0]] != wins[m.snakes[1]] {
+			break
+		}
+		game := t.newGame(fmt.Sprintf("%s-game-%d", m.id, g), m.stage, m.snakes[:], [3]int{m.round, m.index, g})
+		if err := t.play(game); err != nil {
+			return err
+		}
+		if !game.IsDraw && game.WinnerName != "" {
+			wins[game.WinnerName]++
+		}
+		if wins[m.snakes[0]] >= needed || wins[m.snakes[1]] >= needed {
+			break
+		}
+	}
+
+	m.winner, m.loser = m.snakes[0], m.snakes[1]
+	if wins[m.snakes[1]] > wins[m.snakes[0]] || (wins[m.snakes[1]] == wins[m.snakes[0]] && t.seeds[m.snakes[1]] < t.seeds[m.snakes[0]]) {
+		m.winner, m.loser = m.snakes[1], m.snakes[0]
+	}
+	return nil
+}
+
+// tally adds up the points and results of every participant from the games they played.
+func (t *tournament) tally(games []*tournamentGame) []Standing {
+	standings := make([]Standing, 0, len(t.participants))
+	index := map[string]int{}
+	for i, participant := range t.participants {
+		standings = append(standings, Standing{Name: participant.Name, Author: participant.Author})
+		index[participant.Name] = i
+	}
+
+	for _, game := range games {
+		for _, placement := range game.Placements {
+			i, ok := index[placement.Name]
+			if !ok {
+				continue
+			}
+			s := &standings[i]
+			s.Games++
+			s.Kills += placement.Stats.Kills
+			// Snakes that share first place in a draw each score the points for first place
+			if placement.Place <= len(t.opts.Points) {
+				s.Points += t.opts.Points[placement.Place-1]
+			}
+			switch {
+			case placement.Place == 1 && game.IsDraw:
+				s.Draws++
+			case placement.Place == 1:
+				s.Wins++
+			default:
+				s.Losses++
+			}
+		}
+	}
+	return standings
+}
+
+// writeResults writes the standings and every game to tournament.json in the output directory.
+func (t *tournament) writeResults(standings []Standing) error {
+	if t.opts.OutputDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(t.opts.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	sort.SliceStable(t.games, func(i, j int) bool {
+		a, b := t.games[i].order, t.games[j].order
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	data, err := json.MarshalIndent(struct {
+		Format    string            `json:"format"`
+		Seed      int64             `json:"seed"`
+		Standings []Standing        `json:"standings"`
+		Games     []*tournamentGame `json:"games"`
+	}{t.opts.Format, t.template.Seed, standings, t.games}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(t.opts.OutputDir, "tournament.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	log.INFO.Printf("Wrote standings to %s", path)
+	return nil
+}
+
+func printStandings(w io.Writer, standings []Standing) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLACE\tGROUP\tNAME\tPOINTS\tGAMES\tWINS\tDRAWS\tLOSSES\tKILLS\tELIMINATED")
+	for _, s := range standings {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n", s.Place, s.Group, s.Name, s.Points, s.Games, s.Wins, s.Draws, s.Losses, s.Kills, s.EliminatedIn)
+	}
+	return tw.Flush()
+}
+
+// splitGroups deals participants into groups in seeding order, snaking back and forth so each group gets
+// a similar spread of seeds.
+func splitGroups(participants []Participant, numGroups int) [][]Participant {
+	groups := make([][]Participant, numGroups)
+	for i, participant := range participants {
+		g := i % numGroups
+		if (i/numGroups)%2 == 1 {
+			g = numGroups - 1 - g
+		}
+		groups[g] = append(groups[g], participant)
+	}
+	return groups
+}
+
+// combinations returns every way of choosing k of the indexes 0 to n-1, in lexicographic order.
+func combinations(n, k int) [][]int {
+	var result [][]int
+	combination := make([]int, k)
+	var choose func(start, depth int)
+	choose = func(start, depth int) {
+		if depth == k {
+			result = append(result, append([]int{}, combination...))
+			return
+		}
+		for i := start; i <= n-(k-depth); i++ {
+			combination[depth] = i
+			choose(i+1, depth+1)
+		}
+	}
+	choose(0, 0)
+	return result
+}
+
+// pairBySeed pairs snakes for a round, best seed against worst. When the number of snakes isn't a power of two,
+// the best seeds get a bye so that the next round has a power of two.
+func pairBySeed(snakes []string) [][2]string {
+	if len(snakes) < 2 {
+		return [][2]string{}
+	}
+	size := 1
+	for size < len(snakes) {
+		size *= 2
+	}
+	playing := snakes[size-len(snakes):]
+
+	pairs := make([][2]string, 0, len(playing)/2)
+	for i := 0; i < len(playing)/2; i++ {
+		pairs = append(pairs, [2]string{playing[i], playing[len(playing)-1-i]})
+	}
+	return pairs
+}
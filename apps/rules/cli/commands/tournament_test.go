@@ -0,0 +1,284 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func buildParticipants(names ...string) []Participant {
+	participants := make([]Participant, 0, len(names))
+	for _, name := range names {
+		participants = append(participants, Participant{Name: name, URL: "http://" + name + ".example.com"})
+	}
+	return participants
+}
+
+// buildTestTournament creates a tournament where games are decided by the participants' seeds, with the best seed
+// winning unless every game is a draw.
+func buildTestTournament(t *testing.T, participants []Participant, opts tournamentOptions, draws bool) *tournament {
+	if opts.Games == 0 {
+		opts.Games = 1
+	}
+	if opts.Parallel == 0 {
+		opts.Parallel = 2
+	}
+	if opts.Points == nil {
+		opts.Points = []int{2, 1}
+	}
+	tour, err := newTournament(participants, GameState{Seed: 1}, opts)
+	require.NoError(t, err)
+
+	tour.playGame = func(game *tournamentGame) error {
+		snakes := append([]string{}, game.Snakes...)
+		for i := 1; i < len(snakes); i++ {
+			for j := i; j > 0 && tour.seeds[snakes[j]] < tour.seeds[snakes[j-1]]; j-- {
+				snakes[j], snakes[j-1] = snakes[j-1], snakes[j]
+			}
+		}
+		for i, name := range snakes {
+			place := i + 1
+			if draws {
+				place = 1
+			}
+			game.Placements = append(game.Placements, Placement{Place: place, Name: name, Stats: SnakeStats{Kills: len(snakes) - 1 - i}})
+		}
+		game.IsDraw = draws
+		if !draws {
+			game.WinnerName = snakes[0]
+		}
+		return nil
+	}
+	return tour
+}
+
+func standingPlaces(standings []Standing) map[string]int {
+	places := map[string]int{}
+	for _, s := range standings {
+		places[s.Name] = s.Place
+	}
+	return places
+}
+
+func TestTournamentRoundRobin(t *testing.T) {
+	tour := buildTestTournament(t, buildParticipants("a", "b", "c", "d", "e"), tournamentOptions{Format: FormatRoundRobin, Groups: 1, GroupSize: 3, Games: 2}, false)
+
+	standings, err := tour.run()
+	require.NoError(t, err)
+
+	// 10 combinations of 3 snakes, twice each
+	require.Len(t, tour.games, 20)
+	require.Equal(t, []Standing{
+		{Place: 1, Name: "a", Group: "A", Points: 24, Games: 12, Wins: 12, Kills: 24},
+		{Place: 2, Name: "b", Group: "A", Points: 18, Games: 12, Wins: 6, Losses: 6, Kills: 18},
+		{Place: 3, Name: "c", Group: "A", Points: 12, Games: 12, Wins: 2, Losses: 10, Kills: 12},
+		{Place: 4, Name: "d", Group: "A", Points: 6, Games: 12, Losses: 12, Kills: 6},
+		{Place: 5, Name: "e", Group: "A", Points: 0, Games: 12, Losses: 12},
+	}, standings)
+}
+
+func TestTournamentRoundRobinGroups(t *testing.T) {
+	tour := buildTestTournament(t, buildParticipants("a", "b", "c", "d", "e"), tournamentOptions{Format: FormatRoundRobin, Groups: 2, GroupSize: 0}, true)
+
+	standings, err := tour.run()
+	require.NoError(t, err)
+
+	// Groups are dealt in seed order, snaking back: A gets a, d, e and B gets b, c
+	require.Len(t, tour.games, 2)
+	require.Equal(t, []string{"a", "d", "e"}, tour.games[0].Snakes)
+	require.Equal(t, "group A", tour.games[0].Stage)
+	require.Equal(t, []string{"b", "c"}, tour.games[1].Snakes)
+	require.Equal(t, map[string]int{"a": 1, "d": 2, "e": 3, "b": 1, "c": 2}, standingPlaces(standings))
+	require.Equal(t, 1, standings[0].Draws)
+	require.Equal(t, "B", standings[4].Group)
+}
+
+func TestTournamentSingleElimination(t *testing.T) {
+	tour := buildTestTournament(t, buildParticipants("a", "b", "c", "d", "e"), tournamentOptions{Format: FormatSingleElimination}, false)
+
+	standings, err := tour.run()
+	require.NoError(t, err)
+
+	stages := []string{}
+	for _, game := range tour.games {
+		stages = append(stages, game.Stage+": "+strings.Join(game.Snakes, " v "))
+	}
+	require.ElementsMatch(t, []string{
+		"round 1: d v e",
+		"round 2: a v d",
+		"round 2: b v c",
+		"final: a v b",
+	}, stages)
+	require.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3, "d": 3, "e": 5}, standingPlaces(standings))
+	require.Equal(t, "", standings[0].EliminatedIn)
+	require.Equal(t, "final", standings[1].EliminatedIn)
+	require.Equal(t, "round 1", standings[4].EliminatedIn)
+}
+
+func TestTournamentDoubleElimination(t *testing.T) {
+	tour := buildTestTournament(t, buildParticipants("a", "b", "c", "d"), tournamentOptions{Format: FormatDoubleElimination}, false)
+
+	standings, err := tour.run()
+	require.NoError(t, err)
+
+	stages := []string{}
+	for _, game := range tour.games {
+		stages = append(stages, game.Stage+": "+strings.Join(game.Snakes, " v "))
+	}
+	require.ElementsMatch(t, []string{
+		"round 1: a v d",
+		"round 1: b v c",
+		"round 2: a v b",
+		"losers round 2: c v d",
+		"losers round 3: b v c",
+		"final: a v b",
+	}, stages)
+	require.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}, standingPlaces(standings))
+	require.Equal(t, "losers round 3", standings[2].EliminatedIn)
+}
+
+func TestTournamentMatchDraws(t *testing.T) {
+	tour := buildTestTournament(t, buildParticipants("a", "b"), tournamentOptions{Format: FormatSingleElimination, Games: 3}, true)
+
+	standings, err := tour.run()
+	require.NoError(t, err)
+
+	// Every game is a draw, so the extra games are played before the higher seed goes through
+	require.Len(t, tour.games, 6)
+	require.Equal(t, "a", standings[0].Name)
+	require.Equal(t, 6, standings[0].Draws)
+}
+
+func TestTournamentBestOf(t *testing.T) {
+	tour := buildTestTournament(t, buildParticipants("a", "b"), tournamentOptions{Format: FormatSingleElimination, Games: 3}, false)
+
+	_, err := tour.run()
+	require.NoError(t, err)
+
+	// The match ends as soon as a snake has won 2 of 3 games
+	require.Len(t, tour.games, 2)
+}
+
+func TestNewTournamentErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		participants []Participant
+		opts         tournamentOptions
+		err          string
+	}{
+		{"too few", buildParticipants("a"), tournamentOptions{Format: FormatRoundRobin, Groups: 1, GroupSize: 2, Games: 1, Parallel: 1}, "a tournament needs at least 2 participants, got 1"},
+		{"duplicate", buildParticipants("a", "a"), tournamentOptions{Format: FormatRoundRobin, Groups: 1, GroupSize: 2, Games: 1, Parallel: 1}, `participant names must be unique, "a" is used more than once`},
+		{"no url", []Participant{{Name: "a"}, {Name: "b", URL: "http://b"}}, tournamentOptions{Format: FormatRoundRobin, Groups: 1, GroupSize: 2, Games: 1, Parallel: 1}, "participant 1 needs a name and a URL"},
+		{"format", buildParticipants("a", "b"), tournamentOptions{Format: "swiss", Games: 1, Parallel: 1}, `unknown tournament format "swiss"`},
+		{"groups", buildParticipants("a", "b", "c"), tournamentOptions{Format: FormatRoundRobin, Groups: 2, GroupSize: 2, Games: 1, Parallel: 1}, "3 participants can't be split into 2 groups of at least 2"},
+		{"group size", buildParticipants("a", "b"), tournamentOptions{Format: FormatRoundRobin, Groups: 1, GroupSize: 1, Games: 1, Parallel: 1}, "group size must be at least 2, got 1"},
+		{"games", buildParticipants("a", "b"), tournamentOptions{Format: FormatSingleElimination, Games: 0, Parallel: 1}, "games must be at least 1, got 0"},
+		{"parallel", buildParticipants("a", "b"), tournamentOptions{Format: FormatSingleElimination, Games: 1, Parallel: 0}, "parallel must be at least 1, got 0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newTournament(test.participants, GameState{}, test.opts)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestTournamentRunsGames(t *testing.T) {
+	outputDir := t.TempDir()
+	template := *buildDefaultGameState()
+	template.MaxTurns = 3
+	tour, err := newTournament(buildParticipants("a", "b", "c"), template, tournamentOptions{
+		Format: FormatRoundRobin, Groups: 1, GroupSize: 2, Games: 1, Points: []int{2, 1}, Parallel: 3, OutputDir: outputDir,
+	})
+	require.NoError(t, err)
+	tour.httpClient = stubHTTPClient{nil, 200, func(_ string) string { return `{"move": "down"}` }, time.Millisecond}
+
+	standings, err := tour.run()
+	require.NoError(t, err)
+	require.NoError(t, tour.writeResults(standings))
+
+	require.Len(t, tour.games, 3)
+	for _, game := range tour.games {
+		require.Len(t, game.Placements, 2)
+		contents, err := os.ReadFile(filepath.Join(outputDir, "games", game.ID+".jsonl"))
+		require.NoError(t, err)
+		require.Contains(t, string(contents), `"placements":[`)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(outputDir, "tournament.json"))
+	require.NoError(t, err)
+	var written struct {
+		Standings []Standing
+		Games     []tournamentGame
+	}
+	require.NoError(t, json.Unmarshal(contents, &written))
+	require.Equal(t, standings, written.Standings)
+	require.Equal(t, "group-a-1-1", written.Games[0].ID)
+	for _, s := range written.Standings {
+		require.Equal(t, 2, s.Games)
+	}
+}
+
+func TestLoadParticipants(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "participants.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte("participants:\n  - name: a\n    url: http://a\n    author: Ann\n  - name: b\n    url: http://b\n"), 0644))
+	jsonPath := filepath.Join(dir, "participants.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"participants": [{"name": "a", "url": "http://a", "author": "Ann"}, {"name": "b", "url": "http://b"}]}`), 0644))
+
+	expected := []Participant{{Name: "a", URL: "http://a", Author: "Ann"}, {Name: "b", URL: "http://b"}}
+	for _, path := range []string{yamlPath, jsonPath} {
+		participants, err := loadParticipants(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, participants)
+	}
+
+	_, err := loadParticipants(filepath.Join(dir, "participants.txt"))
+	require.Error(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "participants.txt"), []byte("a"), 0644))
+	_, err = loadParticipants(filepath.Join(dir, "participants.txt"))
+	require.EqualError(t, err, `unsupported participants file extension ".txt", expected .yaml, .yml or .json`)
+}
+
+func TestCombinations(t *testing.T) {
+	require.Equal(t, [][]int{{0, 1}, {0, 2}, {1, 2}}, combinations(3, 2))
+	require.Equal(t, [][]int{{0, 1, 2}}, combinations(3, 3))
+	require.Len(t, combinations(7, 3), 35)
+}
+
+func TestSplitGroups(t *testing.T) {
+	groups := splitGroups(buildParticipants("1", "2", "3", "4", "5", "6", "7"), 3)
+	names := [][]string{}
+	for _, group := range groups {
+		groupNames := []string{}
+		for _, participant := range group {
+			groupNames = append(groupNames, participant.Name)
+		}
+		names = append(names, groupNames)
+	}
+	require.Equal(t, [][]string{{"1", "6", "7"}, {"2", "5"}, {"3", "4"}}, names)
+}
+
+func TestPairBySeed(t *testing.T) {
+	tests := []struct {
+		snakes   []string
+		expected [][2]string
+	}{
+		{[]string{}, [][2]string{}},
+		{[]string{"1"}, [][2]string{}},
+		{[]string{"1", "2"}, [][2]string{{"1", "2"}}},
+		{[]string{"1", "2", "3"}, [][2]string{{"2", "3"}}},
+		{[]string{"1", "2", "3", "4"}, [][2]string{{"1", "4"}, {"2", "3"}}},
+		{[]string{"1", "2", "3", "4", "5", "6"}, [][2]string{{"3", "6"}, {"4", "5"}}},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, pairBySeed(test.snakes))
+	}
+}
@@ -25,6 +25,7 @@ func exampleSnakeRequest() SnakeRequest {
 				{
 					ID:      "snake-0",
 					Name:    "snake-0-name",
+					Squad:   "snake-0-squad",
 					Latency: "snake-0-latency",
 					Health:  100,
 					Body:    []Coord{{X: 1, Y: 2}, {X: 1, Y: 3}, {X: 1, Y: 4}},
@@ -40,6 +41,7 @@ func exampleSnakeRequest() SnakeRequest {
 				{
 					ID:      "snake-1",
 					Name:    "snake-1-name",
+					Squad:   "snake-1-squad",
 					Latency: "snake-1-latency",
 					Health:  200,
 					Body:    []Coord{{X: 2, Y: 2}, {X: 2, Y: 3}, {X: 2, Y: 4}},
@@ -53,11 +55,13 @@ func exampleSnakeRequest() SnakeRequest {
 					},
 				},
 			},
-			Food: []Coord{{X: 2, Y: 2}},
+			Food:    []Coord{{X: 2, Y: 2}},
+			Hazards: []Coord{{X: 8, Y: 5}},
 		},
 		You: Snake{
 			ID:      "snake-1",
 			Name:    "snake-1-name",
+			Squad:   "snake-1-squad",
 			Latency: "snake-1-latency",
 			Health:  200,
 			Body:    []Coord{{X: 2, Y: 2}, {X: 2, Y: 3}, {X: 2, Y: 4}},
@@ -74,5 +78,12 @@ func exampleSnakeRequest() SnakeRequest {
 }
 
 var exampleRulesetSettings = settings.NewSettings(map[string]string{
-	rules.ParamFoodSpawnChance: "10",
+	rules.ParamFoodSpawnChance:     "10",
+	rules.ParamMinimumFood:         "20",
+	rules.ParamHazardDamagePerTurn: "30",
+	rules.ParamShrinkEveryNTurns:   "40",
+	rules.ParamAllowBodyCollisions: "true",
+	rules.ParamSharedElimination:   "false",
+	rules.ParamSharedHealth:        "true",
+	rules.ParamSharedLength:        "false",
 })
@@ -24,16 +24,18 @@ type Game struct {
 
 // Board provides information about the game board
 type Board struct {
-	Height int     `json:"height"`
-	Width  int     `json:"width"`
-	Snakes []Snake `json:"snakes"`
-	Food   []Coord `json:"food"`
+	Height  int     `json:"height"`
+	Width   int     `json:"width"`
+	Snakes  []Snake `json:"snakes"`
+	Food    []Coord `json:"food"`
+	Hazards []Coord `json:"hazards"`
 }
 
 // Snake represents information about a snake in the game
 type Snake struct {
 	ID             string         `json:"id"`
 	Name           string         `json:"name"`
+	Squad          string         `json:"squad"`
 	Latency        string         `json:"latency"`
 	Health         int            `json:"health"`
 	Body           []Coord        `json:"body"`
@@ -56,13 +58,41 @@ type Ruleset struct {
 
 // RulesetSettings contains a static collection of a few settings that are exposed through the API.
 type RulesetSettings struct {
-	FoodSpawnChance int `json:"foodSpawnChance"`
+	FoodSpawnChance     int            `json:"foodSpawnChance"`
+	MinimumFood         int            `json:"minimumFood"`
+	HazardDamagePerTurn int            `json:"hazardDamagePerTurn"`
+	RoyaleSettings      RoyaleSettings `json:"royale"`
+	SquadSettings       SquadSettings  `json:"squad"`
+}
+
+// RoyaleSettings contains settings that are specific to the "royale" game mode
+type RoyaleSettings struct {
+	ShrinkEveryNTurns int `json:"shrinkEveryNTurns"`
+}
+
+// SquadSettings contains settings that are specific to the "squad" game mode
+type SquadSettings struct {
+	AllowBodyCollisions bool `json:"allowBodyCollisions"`
+	SharedElimination   bool `json:"sharedElimination"`
+	SharedHealth        bool `json:"sharedHealth"`
+	SharedLength        bool `json:"sharedLength"`
 }
 
 // Converts a rules.Settings (which can contain arbitrary settings) into the static RulesetSettings used in the client API.
 func ConvertRulesetSettings(settings settings.Settings) RulesetSettings {
 	return RulesetSettings{
-		FoodSpawnChance: settings.Int(rules.ParamFoodSpawnChance, 0),
+		FoodSpawnChance:     settings.Int(rules.ParamFoodSpawnChance, 0),
+		MinimumFood:         settings.Int(rules.ParamMinimumFood, 0),
+		HazardDamagePerTurn: settings.Int(rules.ParamHazardDamagePerTurn, 0),
+		RoyaleSettings: RoyaleSettings{
+			ShrinkEveryNTurns: settings.Int(rules.ParamShrinkEveryNTurns, 0),
+		},
+		SquadSettings: SquadSettings{
+			AllowBodyCollisions: settings.Bool(rules.ParamAllowBodyCollisions, true),
+			SharedElimination:   settings.Bool(rules.ParamSharedElimination, true),
+			SharedHealth:        settings.Bool(rules.ParamSharedHealth, false),
+			SharedLength:        settings.Bool(rules.ParamSharedLength, false),
+		},
 	}
 }
 
@@ -97,3 +127,15 @@ func CoordFromPointArray(ptArray []rules.Point) []Coord {
 	}
 	return a
 }
+
+func PointFromCoord(coord Coord) rules.Point {
+	return rules.Point{X: coord.X, Y: coord.Y}
+}
+
+func PointFromCoordArray(coordArray []Coord) []rules.Point {
+	a := make([]rules.Point, 0)
+	for _, coord := range coordArray {
+		a = append(a, PointFromCoord(coord))
+	}
+	return a
+}
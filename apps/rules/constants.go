@@ -26,6 +26,8 @@ const (
 	EliminatedByOutOfHealth         = "out-of-health"
 	EliminatedByHeadToHeadCollision = "head-collision"
 	EliminatedByOutOfBounds         = "wall-collision"
+	EliminatedByHazard              = "hazard"
+	EliminatedBySquad               = "squad-eliminated"
 
 	// Error constants
 	ErrorTooManySnakes   = RulesetError("too many snakes for fixed start positions")
@@ -37,12 +39,31 @@ const (
 	ErrorNoStages        = RulesetError("no stages")
 	ErrorStageNotFound   = RulesetError("stage not found")
 	ErrorMapNotFound     = RulesetError("map not found")
+	ErrorInvalidSetting  = RulesetError("invalid setting")
 
 	// Ruleset / game type names
-	GameTypeSolo     = "solo"
-	GameTypeStandard = "standard"
+	GameTypeConstrictor = "constrictor"
+	GameTypeRoyale      = "royale"
+	GameTypeSolo        = "solo"
+	GameTypeSquad       = "squad"
+	GameTypeStandard    = "standard"
+	GameTypeWrapped     = "wrapped"
 
 	// Game creation parameter names
-	ParamGameType        = "name"
-	ParamFoodSpawnChance = "foodSpawnChance"
+	ParamGameType            = "name"
+	ParamFoodSpawnChance     = "foodSpawnChance"
+	ParamMinimumFood         = "minimumFood"
+	ParamHazardDamagePerTurn = "hazardDamagePerTurn"
+	ParamShrinkEveryNTurns   = "shrinkEveryNTurns"
+	ParamAllowBodyCollisions = "allowBodyCollisions"
+	ParamSharedElimination   = "sharedElimination"
+	ParamSharedHealth        = "sharedHealth"
+	ParamSharedLength        = "sharedLength"
+	ParamMaxTurns            = "maxTurns"
+	ParamTieBreak            = "tieBreak"
+
+	// Tie-break criteria for games that reach the turn limit
+	TieBreakLength = "length"
+	TieBreakHealth = "health"
+	TieBreakKills  = "kills"
 )
@@ -0,0 +1,34 @@
+package ladder
+
+import "math"
+
+const (
+	// EloK is the most an Elo rating can change by in one game.
+	EloK = 32.0
+	// EloInitialRating is the rating given to new snakes.
+	EloInitialRating = 1500.0
+)
+
+// eloExpected is the expected score of a player rated a against a player rated b.
+func eloExpected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// updateElo rates a multi-player game as a head to head result between every pair of snakes.
+// The changes are scaled by the number of opponents, so a game changes a rating by at most EloK.
+func updateElo(ratings []Rating, results [][]float64) []Rating {
+	updated := make([]Rating, len(ratings))
+	for i := range ratings {
+		change := 0.0
+		for j := range ratings {
+			if i != j {
+				change += results[i][j] - eloExpected(ratings[i].Rating, ratings[j].Rating)
+			}
+		}
+		updated[i] = ratings[i]
+		if len(ratings) > 1 {
+			updated[i].Rating += EloK * change / float64(len(ratings)-1)
+		}
+	}
+	return updated
+}
@@ -0,0 +1,61 @@
+package ladder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateElo(t *testing.T) {
+	tests := []struct {
+		name     string
+		ratings  []float64
+		scores   [][]float64
+		expected []float64
+	}{
+		{
+			name:     "equal ratings, first wins",
+			ratings:  []float64{1500, 1500},
+			scores:   [][]float64{{0, 1}, {0, 0}},
+			expected: []float64{1516, 1484},
+		},
+		{
+			name:     "equal ratings, draw",
+			ratings:  []float64{1500, 1500},
+			scores:   [][]float64{{0, 0.5}, {0.5, 0}},
+			expected: []float64{1500, 1500},
+		},
+		{
+			name:     "higher rating wins",
+			ratings:  []float64{1700, 1500},
+			scores:   [][]float64{{0, 1}, {0, 0}},
+			expected: []float64{1707.69, 1492.31},
+		},
+		{
+			name:     "three snakes in order",
+			ratings:  []float64{1500, 1500, 1500},
+			scores:   [][]float64{{0, 1, 1}, {0, 0, 1}, {0, 0, 0}},
+			expected: []float64{1516, 1500, 1484},
+		},
+		{
+			name:     "three snakes, second place tied",
+			ratings:  []float64{1500, 1500, 1500},
+			scores:   [][]float64{{0, 1, 1}, {0, 0, 0.5}, {0, 0.5, 0}},
+			expected: []float64{1516, 1492, 1492},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ratings := make([]Rating, len(test.ratings))
+			for i, r := range test.ratings {
+				ratings[i] = Rating{Rating: r}
+			}
+			updated := updateElo(ratings, test.scores)
+			require.Len(t, updated, len(test.expected))
+			for i, expected := range test.expected {
+				require.InDelta(t, expected, updated[i].Rating, 0.01)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package ladder
+
+import "math"
+
+// Glicko-2 constants, see http://www.glicko.net/glicko/glicko2.pdf
+const (
+	Glicko2InitialRating     = 1500.0
+	Glicko2InitialDeviation  = 350.0
+	Glicko2InitialVolatility = 0.06
+
+	// glicko2Tau constrains how much the volatility can change between games
+	glicko2Tau = 0.5
+	// glicko2Scale converts between the Glicko and Glicko-2 scales
+	glicko2Scale     = 173.7178
+	glicko2Tolerance = 0.000001
+)
+
+func glicko2G(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func glicko2E(mu, muJ, phiJ float64) float64 {
+	return 1 / (1 + math.Exp(-glicko2G(phiJ)*(mu-muJ)))
+}
+
+// updateGlicko2 rates a multi-player game as a rating period in which every snake played each of the others.
+func updateGlicko2(ratings []Rating, results [][]float64) []Rating {
+	updated := make([]Rating, len(ratings))
+	for i := range ratings {
+		opponents := make([]Rating, 0, len(ratings)-1)
+		scores := make([]float64, 0, len(ratings)-1)
+		for j := range ratings {
+			if i != j {
+				opponents = append(opponents, ratings[j])
+				scores = append(scores, results[i][j])
+			}
+		}
+		updated[i] = glicko2Rate(ratings[i], opponents, scores)
+	}
+	return updated
+}
+
+// glicko2Rate follows the steps of the Glicko-2 algorithm to rate a player after a rating period.
+func glicko2Rate(player Rating, opponents []Rating, scores []float64) Rating {
+	mu := (player.Rating - Glicko2InitialRating) / glicko2Scale
+	phi := player.Deviation / glicko2Scale
+	sigma := player.Volatility
+
+	if len(opponents) == 0 {
+		phiStar := math.Sqrt(phi*phi + sigma*sigma)
+		return Rating{Rating: player.Rating, Deviation: phiStar * glicko2Scale, Volatility: sigma}
+	}
+
+	// Step 3 and 4: the estimated variance and improvement from the game outcomes
+	vInv := 0.0
+	delta := 0.0
+	for j, opponent := range opponents {
+		muJ := (opponent.Rating - Glicko2InitialRating) / glicko2Scale
+		phiJ := opponent.Deviation / glicko2Scale
+		g := glicko2G(phiJ)
+		e := glicko2E(mu, muJ, phiJ)
+		vInv += g * g * e * (1 - e)
+		delta += g * (scores[j] - e)
+	}
+	v := 1 / vInv
+	delta *= v
+
+	// Step 5: the new volatility, found with the Illinois algorithm
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		return ex*(delta*delta-phi*phi-v-ex)/(2*math.Pow(phi*phi+v+ex, 2)) - (x-a)/(glicko2Tau*glicko2Tau)
+	}
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*glicko2Tau) < 0 {
+			k++
+		}
+		B = a - k*glicko2Tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glicko2Tolerance {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	newSigma := math.Exp(A / 2)
+
+	// Step 6 to 8: the new deviation and rating, converted back to the Glicko scale
+	phiStar := math.Sqrt(phi*phi + newSigma*newSigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*delta/v
+
+	return Rating{
+		Rating:     newMu*glicko2Scale + Glicko2InitialRating,
+		Deviation:  newPhi * glicko2Scale,
+		Volatility: newSigma,
+	}
+}
@@ -0,0 +1,45 @@
+package ladder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlicko2Rate(t *testing.T) {
+	// The worked example from http://www.glicko.net/glicko/glicko2.pdf
+	player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	opponents := []Rating{
+		{Rating: 1400, Deviation: 30},
+		{Rating: 1550, Deviation: 100},
+		{Rating: 1700, Deviation: 300},
+	}
+	updated := glicko2Rate(player, opponents, []float64{1, 0, 0})
+	require.InDelta(t, 1464.06, updated.Rating, 0.01)
+	require.InDelta(t, 151.52, updated.Deviation, 0.01)
+	require.InDelta(t, 0.05999, updated.Volatility, 0.00001)
+}
+
+func TestGlicko2RateWithoutGames(t *testing.T) {
+	player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	updated := glicko2Rate(player, nil, nil)
+	require.Equal(t, 1500.0, updated.Rating)
+	require.InDelta(t, 200.27, updated.Deviation, 0.01)
+	require.Equal(t, 0.06, updated.Volatility)
+}
+
+func TestUpdateGlicko2(t *testing.T) {
+	initial := Rating{Rating: Glicko2InitialRating, Deviation: Glicko2InitialDeviation, Volatility: Glicko2InitialVolatility}
+	ratings := []Rating{initial, initial, initial}
+	scores := [][]float64{{0, 1, 1}, {0, 0, 0.5}, {0, 0.5, 0}}
+
+	updated := updateGlicko2(ratings, scores)
+	require.Len(t, updated, 3)
+	require.Greater(t, updated[0].Rating, Glicko2InitialRating)
+	require.Less(t, updated[1].Rating, Glicko2InitialRating)
+	require.InDelta(t, updated[1].Rating, updated[2].Rating, 0.000001)
+	require.InDelta(t, Glicko2InitialRating-updated[1].Rating, (updated[0].Rating-Glicko2InitialRating)/2, 0.001)
+	for _, rating := range updated {
+		require.Less(t, rating.Deviation, Glicko2InitialDeviation)
+	}
+}
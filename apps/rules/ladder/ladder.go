@@ -0,0 +1,284 @@
+// Package ladder keeps ratings for snakes that play each other over many games, so that snakes can be compared
+// with every snake that has been on the ladder, not only the ones they played in a single tournament.
+package ladder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Rating algorithms
+const (
+	AlgorithmElo     = "elo"
+	AlgorithmGlicko2 = "glicko2"
+)
+
+// Rating is a snake's rating. Deviation and Volatility are only used by Glicko-2.
+type Rating struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation,omitempty"`
+	Volatility float64 `json:"volatility,omitempty"`
+}
+
+// Snake is a snake registered on the ladder.
+type Snake struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Author string `json:"author,omitempty"`
+	Rating Rating `json:"rating"`
+	Games  int    `json:"games"`
+	Wins   int    `json:"wins"`
+}
+
+// Result is the place a snake finished a game in. Snakes that finish in the same place drew with each other.
+type Result struct {
+	Name  string `json:"name"`
+	Place int    `json:"place"`
+}
+
+// Entry is a snake's position on the leaderboard.
+type Entry struct {
+	Rank      int     `json:"rank"`
+	Name      string  `json:"name"`
+	Author    string  `json:"author,omitempty"`
+	Rating    float64 `json:"rating"`
+	Deviation float64 `json:"deviation,omitempty"`
+	Games     int     `json:"games"`
+	Wins      int     `json:"wins"`
+}
+
+// Ladder is a set of snakes and their ratings. It is safe to record games from several goroutines at once.
+type Ladder struct {
+	Algorithm string   `json:"algorithm"`
+	Games     int      `json:"games"`
+	Snakes    []*Snake `json:"snakes"`
+
+	mutex sync.Mutex
+}
+
+// New creates an empty ladder that rates snakes with the given algorithm.
+func New(algorithm string) (*Ladder, error) {
+	if algorithm != AlgorithmElo && algorithm != AlgorithmGlicko2 {
+		return nil, fmt.Errorf("unknown rating algorithm %q, must be %s or %s", algorithm, AlgorithmElo, AlgorithmGlicko2)
+	}
+	return &Ladder{Algorithm: algorithm, Snakes: []*Snake{}}, nil
+}
+
+// Load reads a ladder saved with Save.
+func Load(path string) (*Ladder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var saved Ladder
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("error reading ladder %s: %w", path, err)
+	}
+	l, err := New(saved.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ladder %s: %w", path, err)
+	}
+	l.Games = saved.Games
+	l.Snakes = append(l.Snakes, saved.Snakes...)
+	return l, nil
+}
+
+// Save writes the ladder to a file. The file is replaced in one step, so a ladder that is stopped while saving
+// isn't left half written.
+func (l *Ladder) Save(path string) error {
+	l.mutex.Lock()
+	data, err := json.MarshalIndent(l, "", "  ")
+	l.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Register adds a snake to the ladder. Registering a snake again updates its URL and author but keeps its rating,
+// so register new versions of a snake under a new name to compare them with the old ones.
+func (l *Ladder) Register(name, url, author string) error {
+	if name == "" {
+		return errors.New("snakes must have a name")
+	}
+	if url == "" {
+		return fmt.Errorf("snake %q must have a URL", name)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if snake := l.find(name); snake != nil {
+		snake.URL = url
+		snake.Author = author
+		return nil
+	}
+	l.Snakes = append(l.Snakes, &Snake{
+		Name:   name,
+		URL:    url,
+		Author: author,
+		Rating: l.initialRating(),
+	})
+	return nil
+}
+
+// Snake returns a copy of the named snake.
+func (l *Ladder) Snake(name string) (Snake, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if snake := l.find(name); snake != nil {
+		return *snake, true
+	}
+	return Snake{}, false
+}
+
+func (l *Ladder) find(name string) *Snake {
+	for _, snake := range l.Snakes {
+		if snake.Name == name {
+			return snake
+		}
+	}
+	return nil
+}
+
+func (l *Ladder) initialRating() Rating {
+	if l.Algorithm == AlgorithmGlicko2 {
+		return Rating{Rating: Glicko2InitialRating, Deviation: Glicko2InitialDeviation, Volatility: Glicko2InitialVolatility}
+	}
+	return Rating{Rating: EloInitialRating}
+}
+
+// Schedule picks the snakes for the next game. The snake that has played the fewest games is always picked,
+// so new snakes get a rating quickly, and the rest are picked at random.
+func (l *Ladder) Schedule(r *rand.Rand, players int) ([]Snake, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if players < 2 {
+		return nil, fmt.Errorf("games need at least 2 snakes, got %d", players)
+	}
+	if len(l.Snakes) < players {
+		return nil, fmt.Errorf("%d snakes are registered, need at least %d", len(l.Snakes), players)
+	}
+
+	snakes := make([]Snake, 0, len(l.Snakes))
+	for _, snake := range l.Snakes {
+		snakes = append(snakes, *snake)
+	}
+	r.Shuffle(len(snakes), func(i, j int) { snakes[i], snakes[j] = snakes[j], snakes[i] })
+	first := 0
+	for i, snake := range snakes {
+		if snake.Games < snakes[first].Games {
+			first = i
+		}
+	}
+	snakes[0], snakes[first] = snakes[first], snakes[0]
+	return snakes[:players], nil
+}
+
+// Record updates the ratings of the snakes in a game. Every snake is rated as having played each of the others,
+// beating the snakes that placed below it and drawing with the snakes in the same place.
+func (l *Ladder) Record(results []Result) error {
+	if len(results) < 2 {
+		return fmt.Errorf("games need at least 2 snakes, got %d", len(results))
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	snakes := make([]*Snake, len(results))
+	ratings := make([]Rating, len(results))
+	for i, result := range results {
+		snake := l.find(result.Name)
+		if snake == nil {
+			return fmt.Errorf("snake %q isn't registered", result.Name)
+		}
+		for _, other := range snakes[:i] {
+			if other == snake {
+				return fmt.Errorf("snake %q is in the results more than once", result.Name)
+			}
+		}
+		snakes[i] = snake
+		ratings[i] = snake.Rating
+	}
+
+	scores := make([][]float64, len(results))
+	winners := 0
+	for i := range results {
+		scores[i] = make([]float64, len(results))
+		for j := range results {
+			switch {
+			case results[i].Place < results[j].Place:
+				scores[i][j] = 1
+			case results[i].Place == results[j].Place:
+				scores[i][j] = 0.5
+			}
+		}
+		if results[i].Place == 1 {
+			winners++
+		}
+	}
+
+	var updated []Rating
+	if l.Algorithm == AlgorithmGlicko2 {
+		updated = updateGlicko2(ratings, scores)
+	} else {
+		updated = updateElo(ratings, scores)
+	}
+	for i, snake := range snakes {
+		snake.Rating = updated[i]
+		snake.Games++
+		if results[i].Place == 1 && winners == 1 {
+			snake.Wins++
+		}
+	}
+	l.Games++
+	return nil
+}
+
+// Leaderboard ranks the snakes by rating. Snakes with the same rounded rating share a rank.
+func (l *Ladder) Leaderboard() []Entry {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	entries := make([]Entry, 0, len(l.Snakes))
+	for _, snake := range l.Snakes {
+		entries = append(entries, Entry{
+			Name:      snake.Name,
+			Author:    snake.Author,
+			Rating:    snake.Rating.Rating,
+			Deviation: snake.Rating.Deviation,
+			Games:     snake.Games,
+			Wins:      snake.Wins,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Rating != entries[j].Rating {
+			return entries[i].Rating > entries[j].Rating
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && math.Round(entries[i].Rating) == math.Round(entries[i-1].Rating) {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return entries
+}
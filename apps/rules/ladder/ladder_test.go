@@ -0,0 +1,160 @@
+package ladder
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(AlgorithmElo)
+	require.NoError(t, err)
+	_, err = New(AlgorithmGlicko2)
+	require.NoError(t, err)
+	_, err = New("trueskill")
+	require.EqualError(t, err, `unknown rating algorithm "trueskill", must be elo or glicko2`)
+}
+
+func TestRegister(t *testing.T) {
+	l, err := New(AlgorithmGlicko2)
+	require.NoError(t, err)
+
+	require.NoError(t, l.Register("one", "http://one", "ann"))
+	snake, ok := l.Snake("one")
+	require.True(t, ok)
+	require.Equal(t, Snake{
+		Name:   "one",
+		URL:    "http://one",
+		Author: "ann",
+		Rating: Rating{Rating: 1500, Deviation: 350, Volatility: 0.06},
+	}, snake)
+
+	// Registering again keeps the rating
+	require.NoError(t, l.Register("two", "http://two", ""))
+	require.NoError(t, l.Record([]Result{{Name: "one", Place: 1}, {Name: "two", Place: 2}}))
+	rated, _ := l.Snake("one")
+	require.NoError(t, l.Register("one", "http://one/v2", "ann"))
+	snake, _ = l.Snake("one")
+	require.Equal(t, "http://one/v2", snake.URL)
+	require.Equal(t, rated.Rating, snake.Rating)
+	require.Len(t, l.Snakes, 2)
+
+	require.EqualError(t, l.Register("", "http://three", ""), "snakes must have a name")
+	require.EqualError(t, l.Register("three", "", ""), `snake "three" must have a URL`)
+}
+
+func TestRecord(t *testing.T) {
+	l, err := New(AlgorithmElo)
+	require.NoError(t, err)
+	for _, name := range []string{"one", "two", "three"} {
+		require.NoError(t, l.Register(name, "http://"+name, ""))
+	}
+
+	require.NoError(t, l.Record([]Result{{Name: "two", Place: 1}, {Name: "one", Place: 2}, {Name: "three", Place: 3}}))
+	require.Equal(t, 1, l.Games)
+	one, _ := l.Snake("one")
+	two, _ := l.Snake("two")
+	three, _ := l.Snake("three")
+	require.Equal(t, Snake{Name: "two", URL: "http://two", Rating: Rating{Rating: 1516}, Games: 1, Wins: 1}, two)
+	require.Equal(t, Snake{Name: "one", URL: "http://one", Rating: Rating{Rating: 1500}, Games: 1}, one)
+	require.Equal(t, Snake{Name: "three", URL: "http://three", Rating: Rating{Rating: 1484}, Games: 1}, three)
+
+	// Draws for first place aren't wins
+	require.NoError(t, l.Record([]Result{{Name: "one", Place: 1}, {Name: "three", Place: 1}}))
+	one, _ = l.Snake("one")
+	require.Equal(t, 0, one.Wins)
+	require.Equal(t, 2, one.Games)
+
+	require.EqualError(t, l.Record([]Result{{Name: "one", Place: 1}}), "games need at least 2 snakes, got 1")
+	require.EqualError(t, l.Record([]Result{{Name: "one", Place: 1}, {Name: "four", Place: 2}}), `snake "four" isn't registered`)
+	require.EqualError(t, l.Record([]Result{{Name: "one", Place: 1}, {Name: "one", Place: 2}}), `snake "one" is in the results more than once`)
+	require.Equal(t, 2, l.Games)
+}
+
+func TestSchedule(t *testing.T) {
+	l, err := New(AlgorithmElo)
+	require.NoError(t, err)
+	require.NoError(t, l.Register("one", "http://one", ""))
+
+	r := rand.New(rand.NewSource(1))
+	_, err = l.Schedule(r, 2)
+	require.EqualError(t, err, "1 snakes are registered, need at least 2")
+
+	for _, name := range []string{"two", "three", "four"} {
+		require.NoError(t, l.Register(name, "http://"+name, ""))
+	}
+	_, err = l.Schedule(r, 1)
+	require.EqualError(t, err, "games need at least 2 snakes, got 1")
+
+	// The snake with the fewest games is always picked
+	l.Snakes[0].Games, l.Snakes[1].Games, l.Snakes[2].Games, l.Snakes[3].Games = 5, 5, 1, 5
+	for i := 0; i < 10; i++ {
+		snakes, err := l.Schedule(r, 2)
+		require.NoError(t, err)
+		require.Len(t, snakes, 2)
+		require.Equal(t, "three", snakes[0].Name)
+		require.NotEqual(t, "three", snakes[1].Name)
+	}
+
+	snakes, err := l.Schedule(r, 4)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"one", "two", "three", "four"}, []string{snakes[0].Name, snakes[1].Name, snakes[2].Name, snakes[3].Name})
+}
+
+func TestLeaderboard(t *testing.T) {
+	l, err := New(AlgorithmGlicko2)
+	require.NoError(t, err)
+	for _, name := range []string{"one", "two", "three", "four"} {
+		require.NoError(t, l.Register(name, "http://"+name, "author "+name))
+	}
+	require.NoError(t, l.Record([]Result{{Name: "three", Place: 1}, {Name: "one", Place: 2}}))
+
+	entries := l.Leaderboard()
+	require.Len(t, entries, 4)
+	require.Equal(t, "three", entries[0].Name)
+	require.Equal(t, "author three", entries[0].Author)
+	require.Equal(t, 1, entries[0].Rank)
+	require.Equal(t, 1, entries[0].Games)
+	require.Equal(t, 1, entries[0].Wins)
+	require.Less(t, entries[0].Deviation, Glicko2InitialDeviation)
+
+	// Snakes that haven't played share a rank
+	require.Equal(t, []string{"four", "two"}, []string{entries[1].Name, entries[2].Name})
+	require.Equal(t, []int{2, 2}, []int{entries[1].Rank, entries[2].Rank})
+	require.Equal(t, 1500.0, entries[1].Rating)
+
+	require.Equal(t, "one", entries[3].Name)
+	require.Equal(t, 4, entries[3].Rank)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ladder.json")
+	_, err := Load(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	l, err := New(AlgorithmGlicko2)
+	require.NoError(t, err)
+	require.NoError(t, l.Register("one", "http://one", "ann"))
+	require.NoError(t, l.Register("two", "http://two", ""))
+	require.NoError(t, l.Record([]Result{{Name: "one", Place: 1}, {Name: "two", Place: 2}}))
+	require.NoError(t, l.Save(path))
+	require.NoError(t, l.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, l.Algorithm, loaded.Algorithm)
+	require.Equal(t, l.Games, loaded.Games)
+	require.Equal(t, l.Snakes, loaded.Snakes)
+
+	// Only the ladder file is left in the directory
+	files, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"algorithm":"trueskill"}`), 0644))
+	_, err = Load(path)
+	require.EqualError(t, err, `error reading ladder `+path+`: unknown rating algorithm "trueskill", must be elo or glicko2`)
+}
@@ -0,0 +1,121 @@
+package maps
+
+import (
+	"rules"
+	"rules/settings"
+)
+
+// ArcadeMazeMap is a fixed-size maze where the walls are hazards, snakes start
+// in fixed positions and food only spawns in a few set places.
+type ArcadeMazeMap struct{}
+
+func init() {
+	globalRegistry.RegisterMap("arcade_maze", ArcadeMazeMap{})
+}
+
+// arcadeMazeLayout is drawn top row first: '#' is a hazard wall,
+// 'S' is a snake start position and 'F' is a food spawn location.
+var arcadeMazeLayout = []string{
+	"###################",
+	"#F.......#.......F#",
+	"#.##.###.#.###.##.#",
+	"#.................#",
+	"#.##.#.#####.#.##.#",
+	"#..S.#...#...#.S..#",
+	"####.###.#.###.####",
+	"####.#.......#.####",
+	"####.#.##.##.#.####",
+	"........#F#........",
+	"####.#.#####.#.####",
+	"####.#...S...#.####",
+	"####.#.#####.#.####",
+	"#........#........#",
+	"#.##.###.#.###.##.#",
+	"#..#.....S.....#..#",
+	"##.#.#.#####.#.#.##",
+	"#....#...#...#....#",
+	"#.######.#.######.#",
+	"#F...S.........S.F#",
+	"###################",
+}
+
+func (m ArcadeMazeMap) ID() string {
+	return "arcade_maze"
+}
+
+func (m ArcadeMazeMap) Meta() Metadata {
+	return Metadata{
+		Name:       "Arcade Maze",
+		MinPlayers: 1,
+		MaxPlayers: 6,
+		BoardSizes: FixedSizes(Dimensions{Width: 19, Height: 21}),
+	}
+}
+
+func (m ArcadeMazeMap) SetupBoard(initialBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if err := m.Meta().Validate(initialBoardState); err != nil {
+		return err
+	}
+
+	rand := settings.GetRand(0)
+	walls, starts, foodSpawns := parseLayout(arcadeMazeLayout)
+
+	err := editor.PlaceSnakesRandomlyAtPositions(rand, initialBoardState.Snakes, starts, rules.SnakeStartSize)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range walls {
+		editor.AddHazard(p)
+	}
+
+	if !isFoodDisabled(settings) {
+		for _, p := range foodSpawns {
+			editor.AddFood(p)
+		}
+	}
+
+	return nil
+}
+
+func (m ArcadeMazeMap) PreUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	return nil
+}
+
+func (m ArcadeMazeMap) PostUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if isFoodDisabled(settings) {
+		return nil
+	}
+
+	rand := settings.GetRand(lastBoardState.Turn)
+
+	foodNeeded := checkFoodNeedingPlacement(rand, settings, lastBoardState)
+	if foodNeeded > 0 {
+		_, _, foodSpawns := parseLayout(arcadeMazeLayout)
+		positions := editor.FilterUnoccupiedPoints(foodSpawns, true, false, true)
+		placeFoodRandomlyAtPositions(rand, editor, foodNeeded, positions)
+	}
+
+	return nil
+}
+
+// parseLayout converts a top-down ASCII layout into board coordinates,
+// returning the wall, snake start and food spawn positions.
+func parseLayout(layout []string) (walls, starts, foodSpawns []rules.Point) {
+	height := len(layout)
+	for row, line := range layout {
+		y := height - 1 - row
+		for x, c := range line {
+			p := rules.Point{X: x, Y: y}
+			switch c {
+			case '#':
+				walls = append(walls, p)
+			case 'S':
+				starts = append(starts, p)
+			case 'F':
+				foodSpawns = append(foodSpawns, p)
+			}
+		}
+	}
+	return walls, starts, foodSpawns
+}
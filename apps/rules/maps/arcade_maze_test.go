@@ -0,0 +1,58 @@
+package maps_test
+
+import (
+	"testing"
+
+	"rules"
+	"rules/maps"
+	"rules/settings"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArcadeMazeMapInterface(t *testing.T) {
+	var _ maps.GameMap = maps.ArcadeMazeMap{}
+}
+
+func TestArcadeMazeMapSetupBoard(t *testing.T) {
+	m := maps.ArcadeMazeMap{}
+	s := settings.Settings{}.WithRand(rules.MinRand)
+
+	boardState := rules.NewBoardState(19, 21).WithSnakes(generateSnakes(6))
+	err := m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.NoError(t, err)
+	require.Equal(t, []rules.Point{{X: 1, Y: 19}, {X: 17, Y: 19}, {X: 9, Y: 11}, {X: 1, Y: 1}, {X: 17, Y: 1}}, boardState.Food)
+	require.Len(t, boardState.Hazards, 210)
+
+	hazards := map[rules.Point]bool{}
+	for _, p := range boardState.Hazards {
+		hazards[p] = true
+	}
+	require.True(t, hazards[rules.Point{X: 0, Y: 0}])
+	require.False(t, hazards[rules.Point{X: 0, Y: 11}], "side tunnel should be open")
+
+	for _, snake := range boardState.Snakes {
+		require.Len(t, snake.Body, rules.SnakeStartSize)
+		require.False(t, hazards[snake.Body[0]], "snake %s starts in a wall", snake.ID)
+	}
+	require.Equal(t, rules.Point{X: 3, Y: 15}, boardState.Snakes[0].Body[0])
+
+	boardState = rules.NewBoardState(19, 21).WithSnakes(generateSnakes(7))
+	err = m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.Error(t, err)
+
+	boardState = rules.NewBoardState(11, 11).WithSnakes(generateSnakes(2))
+	err = m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.Error(t, err)
+}
+
+func TestArcadeMazeMapUpdateBoard(t *testing.T) {
+	m := maps.ArcadeMazeMap{}
+	s := settings.NewSettingsWithParams(rules.ParamMinimumFood, "3").WithRand(rules.MinRand)
+
+	boardState := rules.NewBoardState(19, 21).WithFood([]rules.Point{{X: 1, Y: 19}})
+	nextBoardState := boardState.Clone()
+	err := m.PostUpdateBoard(boardState, s, maps.NewBoardStateEditor(nextBoardState))
+	require.NoError(t, err)
+	require.Equal(t, []rules.Point{{X: 1, Y: 19}, {X: 17, Y: 19}, {X: 9, Y: 11}}, nextBoardState.Food)
+}
@@ -0,0 +1,74 @@
+package maps
+
+import (
+	"rules"
+	"rules/settings"
+)
+
+// CornerFoodMap uses the default snake placement, but food only ever spawns in the four corners of the board.
+type CornerFoodMap struct{}
+
+func init() {
+	globalRegistry.RegisterMap("corner_food", CornerFoodMap{})
+}
+
+func (m CornerFoodMap) ID() string {
+	return "corner_food"
+}
+
+func (m CornerFoodMap) Meta() Metadata {
+	return Metadata{
+		Name:       "Corner Food",
+		MinPlayers: 1,
+		MaxPlayers: 8,
+		BoardSizes: OddSizes(rules.BoardSizeSmall, rules.BoardSizeXXLarge),
+	}
+}
+
+func (m CornerFoodMap) SetupBoard(initialBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if err := m.Meta().Validate(initialBoardState); err != nil {
+		return err
+	}
+
+	if err := (EmptyMap{}).SetupBoard(initialBoardState, settings, editor); err != nil {
+		return err
+	}
+
+	if isFoodDisabled(settings) {
+		return nil
+	}
+	for _, corner := range m.corners(initialBoardState) {
+		editor.AddFood(corner)
+	}
+
+	return nil
+}
+
+func (m CornerFoodMap) PreUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	return nil
+}
+
+func (m CornerFoodMap) PostUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if isFoodDisabled(settings) {
+		return nil
+	}
+
+	rand := settings.GetRand(lastBoardState.Turn)
+
+	foodNeeded := checkFoodNeedingPlacement(rand, settings, lastBoardState)
+	if foodNeeded > 0 {
+		positions := editor.FilterUnoccupiedPoints(m.corners(lastBoardState), true, false, true)
+		placeFoodRandomlyAtPositions(rand, editor, foodNeeded, positions)
+	}
+
+	return nil
+}
+
+func (m CornerFoodMap) corners(b *rules.BoardState) []rules.Point {
+	return []rules.Point{
+		{X: 0, Y: 0},
+		{X: 0, Y: b.Height - 1},
+		{X: b.Width - 1, Y: 0},
+		{X: b.Width - 1, Y: b.Height - 1},
+	}
+}
@@ -0,0 +1,81 @@
+package maps_test
+
+import (
+	"testing"
+
+	"rules"
+	"rules/maps"
+	"rules/settings"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCornerFoodMapInterface(t *testing.T) {
+	var _ maps.GameMap = maps.CornerFoodMap{}
+}
+
+func TestCornerFoodMapSetupBoard(t *testing.T) {
+	m := maps.CornerFoodMap{}
+
+	boardState := rules.NewBoardState(7, 7).WithSnakes(generateSnakes(2))
+	err := m.SetupBoard(boardState, settings.Settings{}.WithRand(rules.MinRand), maps.NewBoardStateEditor(boardState))
+	require.NoError(t, err)
+	require.Equal(t, []rules.Point{{X: 0, Y: 0}, {X: 0, Y: 6}, {X: 6, Y: 0}, {X: 6, Y: 6}}, boardState.Food)
+	require.Len(t, boardState.Snakes, 2)
+
+	boardState = rules.NewBoardState(7, 7).WithSnakes(generateSnakes(2))
+	s := settings.NewSettingsWithParams(rules.ParamGameType, rules.GameTypeConstrictor).WithRand(rules.MinRand)
+	err = m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.NoError(t, err)
+	require.Equal(t, []rules.Point{}, boardState.Food)
+
+	boardState = rules.NewBoardState(8, 8)
+	err = m.SetupBoard(boardState, settings.Settings{}, maps.NewBoardStateEditor(boardState))
+	require.Error(t, err)
+}
+
+func TestCornerFoodMapUpdateBoard(t *testing.T) {
+	m := maps.CornerFoodMap{}
+
+	tests := []struct {
+		name              string
+		initialBoardState *rules.BoardState
+		settings          settings.Settings
+		expectedFood      []rules.Point
+	}{
+		{
+			"FoodSpawnChance inactive",
+			rules.NewBoardState(7, 7),
+			settings.NewSettingsWithParams(rules.ParamFoodSpawnChance, "50").WithRand(rules.MinRand),
+			[]rules.Point{},
+		},
+		{
+			"FoodSpawnChance active",
+			rules.NewBoardState(7, 7),
+			settings.NewSettingsWithParams(rules.ParamFoodSpawnChance, "50").WithRand(rules.MaxRand),
+			[]rules.Point{{X: 0, Y: 6}},
+		},
+		{
+			"MinimumFood fills corners only",
+			rules.NewBoardState(7, 7).WithFood([]rules.Point{{X: 3, Y: 3}}),
+			settings.NewSettingsWithParams(rules.ParamMinimumFood, "10").WithRand(rules.MinRand),
+			[]rules.Point{{X: 3, Y: 3}, {X: 0, Y: 0}, {X: 0, Y: 6}, {X: 6, Y: 0}, {X: 6, Y: 6}},
+		},
+		{
+			"occupied corners are skipped",
+			rules.NewBoardState(7, 7).
+				WithFood([]rules.Point{{X: 0, Y: 0}}).
+				WithSnakes([]rules.Snake{{ID: "1", Body: []rules.Point{{X: 6, Y: 6}, {X: 6, Y: 5}}}}),
+			settings.NewSettingsWithParams(rules.ParamMinimumFood, "10").WithRand(rules.MinRand),
+			[]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 6}, {X: 6, Y: 0}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nextBoardState := test.initialBoardState.Clone()
+			err := m.PostUpdateBoard(test.initialBoardState.Clone(), test.settings, maps.NewBoardStateEditor(nextBoardState))
+			require.NoError(t, err)
+			require.Equal(t, test.expectedFood, nextBoardState.Food)
+		})
+	}
+}
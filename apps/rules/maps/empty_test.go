@@ -0,0 +1,42 @@
+package maps_test
+
+import (
+	"testing"
+
+	"rules"
+	"rules/maps"
+	"rules/settings"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmptyMapInterface(t *testing.T) {
+	var _ maps.GameMap = maps.EmptyMap{}
+}
+
+func TestEmptyMapSetupBoard(t *testing.T) {
+	m := maps.EmptyMap{}
+	s := settings.Settings{}.WithRand(rules.MinRand)
+
+	boardState := rules.NewBoardState(7, 7).WithSnakes(generateSnakes(2))
+	err := m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.NoError(t, err)
+	require.Equal(t, []rules.Point{}, boardState.Food)
+	require.Equal(t, []rules.Point{}, boardState.Hazards)
+	require.Equal(t, []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 1}}, boardState.Snakes[0].Body)
+	require.Equal(t, []rules.Point{{X: 1, Y: 5}, {X: 1, Y: 5}, {X: 1, Y: 5}}, boardState.Snakes[1].Body)
+
+	boardState = rules.NewBoardState(7, 7).WithSnakes(generateSnakes(17))
+	err = m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.Equal(t, rules.ErrorTooManySnakes, err)
+}
+
+func TestEmptyMapUpdateBoard(t *testing.T) {
+	m := maps.EmptyMap{}
+	s := settings.NewSettingsWithParams(rules.ParamFoodSpawnChance, "100", rules.ParamMinimumFood, "5").WithRand(rules.MaxRand)
+
+	boardState := rules.NewBoardState(7, 7)
+	err := m.PostUpdateBoard(boardState.Clone(), s, maps.NewBoardStateEditor(boardState))
+	require.NoError(t, err)
+	require.Equal(t, rules.NewBoardState(7, 7), boardState)
+}
@@ -0,0 +1,313 @@
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"rules"
+	"rules/settings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MapFile is the declarative description of a map, as read from a YAML or JSON file.
+type MapFile struct {
+	ID         string `yaml:"id" json:"id"`
+	Name       string `yaml:"name" json:"name"`
+	MinPlayers int    `yaml:"minPlayers" json:"minPlayers"`
+	MaxPlayers int    `yaml:"maxPlayers" json:"maxPlayers"`
+	Width      int    `yaml:"width" json:"width"`
+	Height     int    `yaml:"height" json:"height"`
+
+	// Layout optionally draws the board top row first, using the same symbols as the
+	// built-in maze maps: '#' is a hazard, 'S' is a snake start position and 'F' is a food spawn zone.
+	// Width and height are taken from the layout when it is set, and must match it if they are also given.
+	Layout []string `yaml:"layout" json:"layout"`
+
+	StartPositions []MapFilePoint `yaml:"startPositions" json:"startPositions"`
+	Hazards        []MapFilePoint `yaml:"hazards" json:"hazards"`
+	Food           MapFileFood    `yaml:"food" json:"food"`
+	Events         []MapFileEvent `yaml:"events" json:"events"`
+}
+
+// MapFilePoint is a board coordinate in a map file.
+type MapFilePoint struct {
+	X int `yaml:"x" json:"x"`
+	Y int `yaml:"y" json:"y"`
+}
+
+// MapFileFood controls where and how often food is placed.
+// SpawnChance and Minimum override the foodSpawnChance and minimumFood game settings when set.
+type MapFileFood struct {
+	Initial     []MapFilePoint `yaml:"initial" json:"initial"`
+	SpawnZones  []MapFilePoint `yaml:"spawnZones" json:"spawnZones"`
+	SpawnChance *int           `yaml:"spawnChance" json:"spawnChance"`
+	Minimum     *int           `yaml:"minimum" json:"minimum"`
+}
+
+// MapFileEvent is a scripted change to the board applied at the end of the given turn.
+type MapFileEvent struct {
+	Turn          int            `yaml:"turn" json:"turn"`
+	AddHazards    []MapFilePoint `yaml:"addHazards" json:"addHazards"`
+	RemoveHazards []MapFilePoint `yaml:"removeHazards" json:"removeHazards"`
+	AddFood       []MapFilePoint `yaml:"addFood" json:"addFood"`
+	RemoveFood    []MapFilePoint `yaml:"removeFood" json:"removeFood"`
+}
+
+// FileMap is a GameMap built from a MapFile.
+type FileMap struct {
+	id             string
+	meta           Metadata
+	startPositions []rules.Point
+	hazards        []rules.Point
+	food           []rules.Point
+	foodSpawnZones []rules.Point
+	foodChance     *int
+	minimumFood    *int
+	events         []MapFileEvent
+}
+
+// LoadMapFile reads a map description from a .yaml, .yml or .json file.
+func LoadMapFile(path string) (GameMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapFile MapFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &mapFile)
+	case ".json":
+		err = json.Unmarshal(data, &mapFile)
+	default:
+		return nil, fmt.Errorf("unsupported map file extension %q, expected .yaml, .yml or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse map file %s: %w", path, err)
+	}
+
+	return NewFileMap(mapFile)
+}
+
+// NewFileMap validates a MapFile and converts it into a GameMap.
+func NewFileMap(mapFile MapFile) (FileMap, error) {
+	m := FileMap{
+		id:          mapFile.ID,
+		foodChance:  mapFile.Food.SpawnChance,
+		minimumFood: mapFile.Food.Minimum,
+		events:      mapFile.Events,
+	}
+
+	width, height := mapFile.Width, mapFile.Height
+	if len(mapFile.Layout) > 0 {
+		height = len(mapFile.Layout)
+		width = len(mapFile.Layout[0])
+		for _, line := range mapFile.Layout {
+			// Layout columns are indexed by byte, so only single byte symbols can be used
+			if utf8.RuneCountInString(line) != len(line) {
+				return FileMap{}, rules.RulesetError("map layout rows must only contain ASCII characters")
+			}
+			if len(line) != width {
+				return FileMap{}, rules.RulesetError("map layout rows must all be the same width")
+			}
+		}
+		if (mapFile.Width != 0 && mapFile.Width != width) || (mapFile.Height != 0 && mapFile.Height != height) {
+			return FileMap{}, rules.RulesetError(fmt.Sprintf("map size %dx%d doesn't match its %dx%d layout", mapFile.Width, mapFile.Height, width, height))
+		}
+		m.hazards, m.startPositions, m.foodSpawnZones = parseLayout(mapFile.Layout)
+	}
+
+	if m.id == "" {
+		return FileMap{}, rules.RulesetError("map file is missing an id")
+	}
+	if width <= 0 || height <= 0 {
+		return FileMap{}, rules.RulesetError("map file must have a positive width and height")
+	}
+
+	m.startPositions = append(m.startPositions, convertMapFilePoints(mapFile.StartPositions)...)
+	m.hazards = append(m.hazards, convertMapFilePoints(mapFile.Hazards)...)
+	m.food = convertMapFilePoints(mapFile.Food.Initial)
+	m.foodSpawnZones = append(m.foodSpawnZones, convertMapFilePoints(mapFile.Food.SpawnZones)...)
+
+	name := mapFile.Name
+	if name == "" {
+		name = mapFile.ID
+	}
+	m.meta = Metadata{
+		Name:       name,
+		MinPlayers: mapFile.MinPlayers,
+		MaxPlayers: mapFile.MaxPlayers,
+		BoardSizes: FixedSizes(Dimensions{Width: width, Height: height}),
+	}
+
+	if err := m.validate(width, height); err != nil {
+		return FileMap{}, err
+	}
+
+	return m, nil
+}
+
+func (m FileMap) validate(width, height int) error {
+	if m.meta.MinPlayers < 0 || m.meta.MaxPlayers < 0 {
+		return rules.RulesetError("map player limits can't be negative")
+	}
+	if m.meta.MaxPlayers != 0 && m.meta.MinPlayers > m.meta.MaxPlayers {
+		return rules.RulesetError("map minPlayers can't be greater than maxPlayers")
+	}
+	if len(m.startPositions) > 0 && m.meta.MaxPlayers > len(m.startPositions) {
+		return rules.RulesetError(fmt.Sprintf("map supports %d players but only has %d start positions", m.meta.MaxPlayers, len(m.startPositions)))
+	}
+	if m.foodChance != nil && (*m.foodChance < 0 || *m.foodChance > 100) {
+		return rules.RulesetError("map food spawnChance must be between 0 and 100")
+	}
+	if m.minimumFood != nil && *m.minimumFood < 0 {
+		return rules.RulesetError("map food minimum can't be negative")
+	}
+
+	inBounds := func(kind string, points []rules.Point) error {
+		for _, p := range points {
+			if p.X < 0 || p.X >= width || p.Y < 0 || p.Y >= height {
+				return rules.RulesetError(fmt.Sprintf("map %s point (%d, %d) is outside the %dx%d board", kind, p.X, p.Y, width, height))
+			}
+		}
+		return nil
+	}
+	checks := map[string][]rules.Point{
+		"start position": m.startPositions,
+		"hazard":         m.hazards,
+		"food":           m.food,
+		"food spawn":     m.foodSpawnZones,
+	}
+	for _, event := range m.events {
+		if event.Turn < 1 {
+			return rules.RulesetError("map event turns must be at least 1")
+		}
+		checks["event"] = append(checks["event"], convertMapFilePoints(event.AddHazards)...)
+		checks["event"] = append(checks["event"], convertMapFilePoints(event.RemoveHazards)...)
+		checks["event"] = append(checks["event"], convertMapFilePoints(event.AddFood)...)
+		checks["event"] = append(checks["event"], convertMapFilePoints(event.RemoveFood)...)
+	}
+	for kind, points := range checks {
+		if err := inBounds(kind, points); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m FileMap) ID() string {
+	return m.id
+}
+
+func (m FileMap) Meta() Metadata {
+	return m.meta
+}
+
+func (m FileMap) SetupBoard(initialBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if err := m.meta.Validate(initialBoardState); err != nil {
+		return err
+	}
+
+	rand := settings.GetRand(0)
+
+	if len(m.startPositions) > 0 {
+		starts := append([]rules.Point(nil), m.startPositions...)
+		err := editor.PlaceSnakesRandomlyAtPositions(rand, initialBoardState.Snakes, starts, rules.SnakeStartSize)
+		if err != nil {
+			return err
+		}
+	} else if err := (EmptyMap{}).SetupBoard(initialBoardState, settings, editor); err != nil {
+		return err
+	}
+
+	for _, p := range m.hazards {
+		editor.AddHazard(p)
+	}
+
+	if !isFoodDisabled(settings) {
+		for _, p := range m.food {
+			editor.AddFood(p)
+		}
+	}
+
+	return nil
+}
+
+func (m FileMap) PreUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	return nil
+}
+
+func (m FileMap) PostUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	turn := lastBoardState.Turn + 1
+	for _, event := range m.events {
+		if event.Turn != turn {
+			continue
+		}
+		for _, p := range convertMapFilePoints(event.RemoveHazards) {
+			editor.RemoveHazard(p)
+		}
+		for _, p := range convertMapFilePoints(event.AddHazards) {
+			editor.AddHazard(p)
+		}
+		for _, p := range convertMapFilePoints(event.RemoveFood) {
+			editor.RemoveFood(p)
+		}
+		if !isFoodDisabled(settings) {
+			for _, p := range convertMapFilePoints(event.AddFood) {
+				editor.AddFood(p)
+			}
+		}
+	}
+
+	if isFoodDisabled(settings) {
+		return nil
+	}
+
+	rand := settings.GetRand(lastBoardState.Turn)
+
+	minFood := settings.Int(rules.ParamMinimumFood, 0)
+	if m.minimumFood != nil {
+		minFood = *m.minimumFood
+	}
+	foodSpawnChance := settings.Int(rules.ParamFoodSpawnChance, 0)
+	if m.foodChance != nil {
+		foodSpawnChance = *m.foodChance
+	}
+
+	foodNeeded := countFoodNeeded(rand, minFood, foodSpawnChance, len(editor.Food()))
+	if foodNeeded > 0 {
+		if len(m.foodSpawnZones) > 0 {
+			positions := editor.FilterUnoccupiedPoints(m.foodSpawnZones, true, false, true)
+			placeFoodRandomlyAtPositions(rand, editor, foodNeeded, positions)
+		} else {
+			positions := editor.FilterUnoccupiedPoints(allPoints(lastBoardState), true, true, true)
+			placeFoodRandomlyAtPositions(rand, editor, foodNeeded, positions)
+		}
+	}
+
+	return nil
+}
+
+func convertMapFilePoints(points []MapFilePoint) []rules.Point {
+	result := make([]rules.Point, 0, len(points))
+	for _, p := range points {
+		result = append(result, rules.Point{X: p.X, Y: p.Y})
+	}
+	return result
+}
+
+func allPoints(b *rules.BoardState) []rules.Point {
+	points := make([]rules.Point, 0, b.Width*b.Height)
+	for x := 0; x < b.Width; x++ {
+		for y := 0; y < b.Height; y++ {
+			points = append(points, rules.Point{X: x, Y: y})
+		}
+	}
+	return points
+}
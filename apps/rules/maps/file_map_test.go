@@ -0,0 +1,138 @@
+package maps_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"rules"
+	"rules/maps"
+	"rules/settings"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMapInterface(t *testing.T) {
+	var _ maps.GameMap = maps.FileMap{}
+}
+
+func TestLoadMapFile(t *testing.T) {
+	for _, path := range []string{"testdata/arena.yaml", "testdata/arena.json"} {
+		t.Run(path, func(t *testing.T) {
+			m, err := maps.LoadMapFile(path)
+			require.NoError(t, err)
+			require.Equal(t, "arena", m.ID())
+
+			meta := m.Meta()
+			require.Equal(t, "Arena", meta.Name)
+			require.Equal(t, 2, meta.MinPlayers)
+			require.Equal(t, 4, meta.MaxPlayers)
+			require.Equal(t, []maps.Dimensions{{Width: 7, Height: 7}}, []maps.Dimensions(meta.BoardSizes))
+
+			boardState := rules.NewBoardState(7, 7).WithSnakes(generateSnakes(4))
+			err = m.SetupBoard(boardState, settings.Settings{}.WithRand(rules.MinRand), maps.NewBoardStateEditor(boardState))
+			require.NoError(t, err)
+			require.Equal(t, []rules.Point{{X: 3, Y: 2}, {X: 3, Y: 4}}, boardState.Hazards)
+			require.Equal(t, []rules.Point{{X: 3, Y: 3}}, boardState.Food)
+			require.Equal(t, []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 1}}, boardState.Snakes[0].Body)
+			require.Equal(t, []rules.Point{{X: 5, Y: 5}, {X: 5, Y: 5}, {X: 5, Y: 5}}, boardState.Snakes[3].Body)
+		})
+	}
+}
+
+func TestLoadMapFileLayout(t *testing.T) {
+	m, err := maps.LoadMapFile("testdata/layout.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "tiny_maze", m.Meta().Name)
+	require.True(t, m.Meta().BoardSizes.IsAllowable(5, 5))
+
+	boardState := rules.NewBoardState(5, 5).WithSnakes(generateSnakes(2))
+	err = m.SetupBoard(boardState, settings.Settings{}.WithRand(rules.MinRand), maps.NewBoardStateEditor(boardState))
+	require.NoError(t, err)
+	require.Len(t, boardState.Hazards, 17)
+	require.Equal(t, rules.Point{X: 1, Y: 3}, boardState.Snakes[0].Body[0])
+	require.Equal(t, rules.Point{X: 3, Y: 1}, boardState.Snakes[1].Body[0])
+
+	nextBoardState, err := maps.PostUpdateBoard(m, boardState, settings.NewSettingsWithParams(rules.ParamMinimumFood, "2").WithRand(rules.MinRand))
+	require.NoError(t, err)
+	require.Equal(t, []rules.Point{{X: 3, Y: 3}, {X: 1, Y: 1}}, nextBoardState.Food)
+}
+
+func TestLoadMapFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		filename string
+		contents string
+	}{
+		{"unknown extension", "map.txt", "id: test"},
+		{"invalid yaml", "map.yaml", "id: [test"},
+		{"invalid json", "map.json", "{"},
+		{"missing id", "map.yaml", "width: 7\nheight: 7"},
+		{"missing size", "map.yaml", "id: test"},
+		{"uneven layout", "map.yaml", "id: test\nlayout: ['...', '..']"},
+		{"non-ascii layout", "map.yaml", "id: test\nlayout: ['.é', '...']"},
+		{"width doesn't match layout", "map.yaml", "id: test\nwidth: 7\nlayout: ['...', '...']"},
+		{"height doesn't match layout", "map.yaml", "id: test\nheight: 3\nlayout: ['...', '...']"},
+		{"min above max players", "map.yaml", "id: test\nwidth: 7\nheight: 7\nminPlayers: 4\nmaxPlayers: 2"},
+		{"too few start positions", "map.yaml", "id: test\nwidth: 7\nheight: 7\nmaxPlayers: 2\nstartPositions: [{x: 1, y: 1}]"},
+		{"hazard out of bounds", "map.yaml", "id: test\nwidth: 7\nheight: 7\nhazards: [{x: 7, y: 1}]"},
+		{"event out of bounds", "map.yaml", "id: test\nwidth: 7\nheight: 7\nevents: [{turn: 2, addFood: [{x: 1, y: -1}]}]"},
+		{"event turn zero", "map.yaml", "id: test\nwidth: 7\nheight: 7\nevents: [{turn: 0}]"},
+		{"spawn chance above 100", "map.yaml", "id: test\nwidth: 7\nheight: 7\nfood: {spawnChance: 101}"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, test.filename)
+			require.NoError(t, os.WriteFile(path, []byte(test.contents), 0644))
+
+			_, err := maps.LoadMapFile(path)
+			require.Error(t, err)
+		})
+	}
+
+	_, err := maps.LoadMapFile(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestFileMapSetupBoardValidatesMeta(t *testing.T) {
+	m, err := maps.LoadMapFile("testdata/arena.yaml")
+	require.NoError(t, err)
+
+	boardState := rules.NewBoardState(11, 11).WithSnakes(generateSnakes(2))
+	err = m.SetupBoard(boardState, settings.Settings{}, maps.NewBoardStateEditor(boardState))
+	require.Error(t, err)
+
+	boardState = rules.NewBoardState(7, 7).WithSnakes(generateSnakes(1))
+	err = m.SetupBoard(boardState, settings.Settings{}, maps.NewBoardStateEditor(boardState))
+	require.Error(t, err)
+}
+
+func TestFileMapUpdateBoard(t *testing.T) {
+	m, err := maps.LoadMapFile("testdata/arena.yaml")
+	require.NoError(t, err)
+	s := settings.Settings{}.WithRand(rules.MinRand)
+
+	// Food only spawns in the spawn zones, and the map's spawn chance overrides the game setting
+	boardState := rules.NewBoardState(7, 7).
+		WithFood([]rules.Point{{X: 3, Y: 3}}).
+		WithHazards([]rules.Point{{X: 3, Y: 2}, {X: 3, Y: 4}})
+	boardState.Turn = 3
+	nextBoardState, err := maps.PostUpdateBoard(m, boardState, settings.NewSettingsWithParams(rules.ParamFoodSpawnChance, "0").WithRand(rules.MaxRand))
+	require.NoError(t, err)
+	require.Equal(t, []rules.Point{{X: 3, Y: 3}, {X: 6, Y: 3}}, nextBoardState.Food)
+	require.Equal(t, []rules.Point{{X: 3, Y: 2}, {X: 3, Y: 4}}, nextBoardState.Hazards)
+
+	// Scripted events run at the end of their turn
+	boardState.Turn = 9
+	nextBoardState, err = maps.PostUpdateBoard(m, boardState, s)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []rules.Point{{X: 3, Y: 4}, {X: 2, Y: 3}, {X: 4, Y: 3}}, nextBoardState.Hazards)
+
+	// Constrictor games never spawn food
+	boardState.Food = []rules.Point{}
+	nextBoardState, err = maps.PostUpdateBoard(m, boardState, settings.NewSettingsWithParams(rules.ParamGameType, rules.GameTypeConstrictor))
+	require.NoError(t, err)
+	require.Equal(t, []rules.Point{}, nextBoardState.Food)
+}
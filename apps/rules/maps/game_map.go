@@ -15,7 +15,7 @@ type GameMap interface {
 	// Return non-functional metadata about this map.
 	Meta() Metadata
 
-	// Called to generate a new board. The map is responsible for placing all snakes, food.
+	// Called to generate a new board. The map is responsible for placing all snakes, food, and hazards.
 	SetupBoard(initialBoardState *rules.BoardState, settings settings.Settings, editor Editor) error
 
 	// Called every turn to optionally update the board before the board is sent to snakes to get their moves.
@@ -145,6 +145,19 @@ type Editor interface {
 	// Note: the return value is a copy and modifying it won't affect the board.
 	Food() []rules.Point
 
+	// Clears all hazards from the board.
+	ClearHazards()
+
+	// Adds a hazard to the board. Does not check for duplicates.
+	AddHazard(rules.Point)
+
+	// Removes all hazards from a specific tile on the board.
+	RemoveHazard(rules.Point)
+
+	// Get the locations of hazards currently on the board.
+	// Note: the return value is a copy and modifying it won't affect the board.
+	Hazards() []rules.Point
+
 	// Updates the body and health of a snake.
 	PlaceSnake(id string, body []rules.Point, health int)
 
@@ -163,15 +176,15 @@ type Editor interface {
 	// Snakes is impossible.
 	PlaceSnakesRandomlyAtPositions(rand rules.Rand, snakes []rules.Snake, heads []rules.Point, bodyLength int) error
 
-	// Returns true if the provided point on the board is occupied by a snake body, food.
-	IsOccupied(point rules.Point, snakes, food bool) bool
+	// Returns true if the provided point on the board is occupied by a snake body, hazard, or food.
+	IsOccupied(point rules.Point, snakes, hazards, food bool) bool
 
-	// Get a set of all points on the board the are occupied by snake bodies, food.
+	// Get a set of all points on the board the are occupied by snake bodies, hazards, or food.
 	// The value for each point will be set to true in the return value if that point is occupied by one of the selected objects.
-	OccupiedPoints(snakes, food bool) map[rules.Point]bool
+	OccupiedPoints(snakes, hazards, food bool) map[rules.Point]bool
 
-	// Given a list of points, return only those that are unoccupied by snake bodies, food.
-	FilterUnoccupiedPoints(targets []rules.Point, snakes, food bool) []rules.Point
+	// Given a list of points, return only those that are unoccupied by snake bodies, hazards, or food.
+	FilterUnoccupiedPoints(targets []rules.Point, snakes, hazards, food bool) []rules.Point
 
 	// Shuffle the provided slice of points randomly using the provided rules.Rand
 	ShufflePoints(rules.Rand, []rules.Point)
@@ -211,6 +224,30 @@ func (editor *BoardStateEditor) Food() []rules.Point {
 	return append([]rules.Point(nil), editor.boardState.Food...)
 }
 
+func (editor *BoardStateEditor) ClearHazards() {
+	editor.boardState.Hazards = []rules.Point{}
+}
+
+func (editor *BoardStateEditor) AddHazard(p rules.Point) {
+	editor.boardState.Hazards = append(editor.boardState.Hazards, rules.Point{X: p.X, Y: p.Y})
+}
+
+func (editor *BoardStateEditor) RemoveHazard(p rules.Point) {
+	for index := len(editor.boardState.Hazards) - 1; index >= 0; index-- {
+		hazard := editor.boardState.Hazards[index]
+		if hazard.X == p.X && hazard.Y == p.Y {
+			editor.boardState.Hazards[index] = editor.boardState.Hazards[len(editor.boardState.Hazards)-1]
+			editor.boardState.Hazards = editor.boardState.Hazards[:len(editor.boardState.Hazards)-1]
+		}
+	}
+}
+
+// Get the locations of hazards currently on the board.
+// Note: the return value is read-only.
+func (editor *BoardStateEditor) Hazards() []rules.Point {
+	return append([]rules.Point(nil), editor.boardState.Hazards...)
+}
+
 func (editor *BoardStateEditor) PlaceSnake(id string, body []rules.Point, health int) {
 	for index, snake := range editor.boardState.Snakes {
 		if snake.ID == id {
@@ -273,8 +310,8 @@ func (editor *BoardStateEditor) PlaceSnakesRandomlyAtPositions(rand rules.Rand,
 	return nil
 }
 
-// Returns true if the provided point on the board is occupied by a snake body, food.
-func (editor *BoardStateEditor) IsOccupied(point rules.Point, snakes, food bool) bool {
+// Returns true if the provided point on the board is occupied by a snake body, hazard, or food.
+func (editor *BoardStateEditor) IsOccupied(point rules.Point, snakes, hazards, food bool) bool {
 	if food {
 		for _, food := range editor.boardState.Food {
 			if food == point {
@@ -282,6 +319,13 @@ func (editor *BoardStateEditor) IsOccupied(point rules.Point, snakes, food bool)
 			}
 		}
 	}
+	if hazards {
+		for _, hazard := range editor.boardState.Hazards {
+			if hazard == point {
+				return true
+			}
+		}
+	}
 	if snakes {
 		for _, snake := range editor.boardState.Snakes {
 			for _, body := range snake.Body {
@@ -294,17 +338,22 @@ func (editor *BoardStateEditor) IsOccupied(point rules.Point, snakes, food bool)
 	return false
 }
 
-// Get a set of all points on the board the are occupied by snake bodies, food.
+// Get a set of all points on the board the are occupied by snake bodies, hazards, or food.
 // The value for each point will be set to true in the return value if that point is occupied by one of the selected objects.
-func (editor *BoardStateEditor) OccupiedPoints(snakes, food bool) map[rules.Point]bool {
+func (editor *BoardStateEditor) OccupiedPoints(snakes, hazards, food bool) map[rules.Point]bool {
 	boardState := editor.boardState
-	result := make(map[rules.Point]bool, len(boardState.Food)+len(boardState.Snakes)*3)
+	result := make(map[rules.Point]bool, len(boardState.Food)+len(boardState.Hazards)+len(boardState.Snakes)*3)
 
 	if food {
 		for _, food := range editor.boardState.Food {
 			result[food] = true
 		}
 	}
+	if hazards {
+		for _, hazard := range editor.boardState.Hazards {
+			result[hazard] = true
+		}
+	}
 	if snakes {
 		for _, snake := range editor.boardState.Snakes {
 			for _, body := range snake.Body {
@@ -316,8 +365,8 @@ func (editor *BoardStateEditor) OccupiedPoints(snakes, food bool) map[rules.Poin
 	return result
 }
 
-// Given a list of points, return only those that are unoccupied by snake bodies, food
-func (editor *BoardStateEditor) FilterUnoccupiedPoints(targets []rules.Point, snakes, food bool) []rules.Point {
+// Given a list of points, return only those that are unoccupied by snake bodies, hazards, or food
+func (editor *BoardStateEditor) FilterUnoccupiedPoints(targets []rules.Point, snakes, hazards, food bool) []rules.Point {
 	result := make([]rules.Point, 0, len(targets))
 
 targetLoop:
@@ -329,6 +378,13 @@ targetLoop:
 				}
 			}
 		}
+		if hazards {
+			for _, hazard := range editor.boardState.Hazards {
+				if hazard == point {
+					continue targetLoop
+				}
+			}
+		}
 		if snakes {
 			for _, snake := range editor.boardState.Snakes {
 				for _, body := range snake.Body {
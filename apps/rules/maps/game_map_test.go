@@ -3,8 +3,8 @@ package maps
 import (
 	"testing"
 
-	"rules"
 	"github.com/stretchr/testify/require"
+	"rules"
 )
 
 func TestMetadataValidate(t *testing.T) {
@@ -121,6 +121,10 @@ func TestBoardStateEditor(t *testing.T) {
 	editor.AddFood(rules.Point{X: 3, Y: 6})
 	editor.AddFood(rules.Point{X: 3, Y: 7})
 	editor.RemoveFood(rules.Point{X: 3, Y: 6})
+	editor.AddHazard(rules.Point{X: 1, Y: 3})
+	editor.AddHazard(rules.Point{X: 3, Y: 6})
+	editor.AddHazard(rules.Point{X: 3, Y: 7})
+	editor.RemoveHazard(rules.Point{X: 3, Y: 6})
 	editor.PlaceSnake("existing_snake", []rules.Point{{X: 5, Y: 2}, {X: 5, Y: 1}, {X: 5, Y: 0}}, 99)
 	editor.PlaceSnake("new_snake", []rules.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}, 98)
 
@@ -129,6 +133,10 @@ func TestBoardStateEditor(t *testing.T) {
 			{X: 1, Y: 3},
 			{X: 3, Y: 7},
 		}).
+		WithHazards([]rules.Point{
+			{X: 1, Y: 3},
+			{X: 3, Y: 7},
+		}).
 		WithSnakes([]rules.Snake{
 			{
 				ID:     "existing_snake",
@@ -148,6 +156,11 @@ func TestBoardStateEditor(t *testing.T) {
 		{X: 3, Y: 7},
 	}, editor.Food())
 
+	require.Equal(t, []rules.Point{
+		{X: 1, Y: 3},
+		{X: 3, Y: 7},
+	}, editor.Hazards())
+
 	require.Equal(t, map[string][]rules.Point{
 		"existing_snake": {
 			{X: 5, Y: 2}, {X: 5, Y: 1}, {X: 5, Y: 0},
@@ -160,6 +173,9 @@ func TestBoardStateEditor(t *testing.T) {
 
 	editor.ClearFood()
 	require.Equal(t, []rules.Point{}, boardState.Food)
+
+	editor.ClearHazards()
+	require.Equal(t, []rules.Point{}, boardState.Hazards)
 }
 
 func TestBoardStateEditorPlaceSnakesRandomlyAtPositions(t *testing.T) {
@@ -247,20 +263,21 @@ func TestBoardStateEditorPlaceSnakesRandomlyAtPositions(t *testing.T) {
 
 func TestBoardStateEditorIsOccupied(t *testing.T) {
 	for label, test := range map[string]struct {
-		boardState   *rules.BoardState
-		point        rules.Point
-		snakes, food bool
-		expected     bool
+		boardState            *rules.BoardState
+		point                 rules.Point
+		snakes, hazards, food bool
+		expected              bool
 	}{
 		"empty board": {
 			rules.NewBoardState(rules.BoardSizeSmall, rules.BoardSizeSmall),
 			rules.Point{X: 3, Y: 3},
-			true, true,
+			true, true, true,
 			false,
 		},
 		"unoccupied": {
 			&rules.BoardState{
-				Food: []rules.Point{{X: 1, Y: 1}},
+				Food:    []rules.Point{{X: 1, Y: 1}},
+				Hazards: []rules.Point{{X: 2, Y: 2}},
 				Snakes: []rules.Snake{
 					{
 						ID:   "1",
@@ -269,7 +286,7 @@ func TestBoardStateEditorIsOccupied(t *testing.T) {
 				},
 			},
 			rules.Point{X: 2, Y: 3},
-			true, true,
+			true, true, true,
 			false,
 		},
 		"food": {
@@ -277,7 +294,7 @@ func TestBoardStateEditorIsOccupied(t *testing.T) {
 				Food: []rules.Point{{X: 1, Y: 1}},
 			},
 			rules.Point{X: 1, Y: 1},
-			false, true,
+			false, false, true,
 			true,
 		},
 		"ignored food": {
@@ -285,7 +302,23 @@ func TestBoardStateEditorIsOccupied(t *testing.T) {
 				Food: []rules.Point{{X: 1, Y: 1}},
 			},
 			rules.Point{X: 1, Y: 1},
-			false, false,
+			false, false, false,
+			false,
+		},
+		"hazard": {
+			&rules.BoardState{
+				Hazards: []rules.Point{{X: 1, Y: 1}},
+			},
+			rules.Point{X: 1, Y: 1},
+			false, true, false,
+			true,
+		},
+		"ignored hazard": {
+			&rules.BoardState{
+				Hazards: []rules.Point{{X: 1, Y: 1}},
+			},
+			rules.Point{X: 1, Y: 1},
+			true, false, true,
 			false,
 		},
 		"snake": {
@@ -298,7 +331,7 @@ func TestBoardStateEditorIsOccupied(t *testing.T) {
 				},
 			},
 			rules.Point{X: 1, Y: 1},
-			true, false,
+			true, false, false,
 			true,
 		},
 		"ignored snake": {
@@ -311,14 +344,14 @@ func TestBoardStateEditorIsOccupied(t *testing.T) {
 				},
 			},
 			rules.Point{X: 1, Y: 1},
-			false, false,
+			false, false, false,
 			false,
 		},
 	} {
 		t.Run(label, func(t *testing.T) {
 			editor := NewBoardStateEditor(test.boardState)
 
-			actual := editor.IsOccupied(test.point, test.snakes, test.food)
+			actual := editor.IsOccupied(test.point, test.snakes, test.hazards, test.food)
 
 			require.Equal(t, test.expected, actual)
 		})
@@ -327,7 +360,8 @@ func TestBoardStateEditorIsOccupied(t *testing.T) {
 
 func TestBoardStateEditorOccupiedPoints(t *testing.T) {
 	testBoardState := &rules.BoardState{
-		Food: []rules.Point{{X: 1, Y: 1}},
+		Food:    []rules.Point{{X: 1, Y: 1}},
+		Hazards: []rules.Point{{X: 2, Y: 2}},
 		Snakes: []rules.Snake{
 			{
 				ID:   "1",
@@ -337,18 +371,18 @@ func TestBoardStateEditorOccupiedPoints(t *testing.T) {
 	}
 
 	for label, test := range map[string]struct {
-		boardState   *rules.BoardState
-		snakes, food bool
-		expected     map[rules.Point]bool
+		boardState            *rules.BoardState
+		snakes, hazards, food bool
+		expected              map[rules.Point]bool
 	}{
 		"empty board": {
 			rules.NewBoardState(rules.BoardSizeSmall, rules.BoardSizeSmall),
-			true, true,
+			true, true, true,
 			map[rules.Point]bool{},
 		},
 		"all types": {
 			testBoardState,
-			true, true,
+			true, true, true,
 			map[rules.Point]bool{
 				{X: 1, Y: 1}: true,
 				{X: 2, Y: 2}: true,
@@ -357,15 +391,23 @@ func TestBoardStateEditorOccupiedPoints(t *testing.T) {
 		},
 		"ignore snakes": {
 			testBoardState,
-			false, true,
+			false, true, true,
 			map[rules.Point]bool{
 				{X: 1, Y: 1}: true,
 				{X: 2, Y: 2}: true,
 			},
 		},
+		"ignore hazards": {
+			testBoardState,
+			true, false, true,
+			map[rules.Point]bool{
+				{X: 1, Y: 1}: true,
+				{X: 3, Y: 3}: true,
+			},
+		},
 		"ignore food": {
 			testBoardState,
-			true, false,
+			true, true, false,
 			map[rules.Point]bool{
 				{X: 2, Y: 2}: true,
 				{X: 3, Y: 3}: true,
@@ -375,7 +417,7 @@ func TestBoardStateEditorOccupiedPoints(t *testing.T) {
 		t.Run(label, func(t *testing.T) {
 			editor := NewBoardStateEditor(test.boardState)
 
-			actual := editor.OccupiedPoints(test.snakes, test.food)
+			actual := editor.OccupiedPoints(test.snakes, test.hazards, test.food)
 
 			require.Equal(t, test.expected, actual)
 		})
@@ -384,7 +426,8 @@ func TestBoardStateEditorOccupiedPoints(t *testing.T) {
 
 func TestBoardStateEditorFilterUnoccupiedPoints(t *testing.T) {
 	testBoardState := &rules.BoardState{
-		Food: []rules.Point{{X: 1, Y: 1}},
+		Food:    []rules.Point{{X: 1, Y: 1}},
+		Hazards: []rules.Point{{X: 2, Y: 2}},
 		Snakes: []rules.Snake{
 			{
 				ID:   "1",
@@ -394,40 +437,46 @@ func TestBoardStateEditorFilterUnoccupiedPoints(t *testing.T) {
 	}
 
 	for label, test := range map[string]struct {
-		boardState   *rules.BoardState
-		targets      []rules.Point
-		snakes, food bool
-		expected     []rules.Point
+		boardState            *rules.BoardState
+		targets               []rules.Point
+		snakes, hazards, food bool
+		expected              []rules.Point
 	}{
 		"empty": {
 			rules.NewBoardState(rules.BoardSizeSmall, rules.BoardSizeSmall),
 			[]rules.Point{},
-			true, true,
+			true, true, true,
 			[]rules.Point{},
 		},
 		"all types": {
 			testBoardState,
 			[]rules.Point{{X: 3, Y: 3}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 1}},
-			true, true,
+			true, true, true,
 			[]rules.Point{{X: 2, Y: 1}},
 		},
 		"ignore snakes": {
 			testBoardState,
 			[]rules.Point{{X: 3, Y: 3}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 1}},
-			false, true,
+			false, true, true,
 			[]rules.Point{{X: 3, Y: 3}, {X: 2, Y: 1}},
 		},
+		"ignore hazards": {
+			testBoardState,
+			[]rules.Point{{X: 3, Y: 3}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 1}},
+			true, false, true,
+			[]rules.Point{{X: 2, Y: 2}, {X: 2, Y: 1}},
+		},
 		"ignore food": {
 			testBoardState,
 			[]rules.Point{{X: 3, Y: 3}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 1}},
-			true, false,
+			true, true, false,
 			[]rules.Point{{X: 1, Y: 1}, {X: 2, Y: 1}},
 		},
 	} {
 		t.Run(label, func(t *testing.T) {
 			editor := NewBoardStateEditor(test.boardState)
 
-			actual := editor.FilterUnoccupiedPoints(test.targets, test.snakes, test.food)
+			actual := editor.FilterUnoccupiedPoints(test.targets, test.snakes, test.hazards, test.food)
 
 			require.Equal(t, test.expected, actual)
 		})
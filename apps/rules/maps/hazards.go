@@ -0,0 +1,147 @@
+package maps
+
+import (
+	"rules"
+	"rules/settings"
+)
+
+// RingsHazardsMap uses the standard board setup and adds static concentric rings of hazards around the center.
+type RingsHazardsMap struct{}
+
+func init() {
+	globalRegistry.RegisterMap("hz_rings", RingsHazardsMap{})
+	globalRegistry.RegisterMap("hz_spiral", SpiralHazardsMap{})
+}
+
+// ringsHazardsSpacing is the distance between each hazard ring.
+const ringsHazardsSpacing = 3
+
+func (m RingsHazardsMap) ID() string {
+	return "hz_rings"
+}
+
+func (m RingsHazardsMap) Meta() Metadata {
+	return Metadata{
+		Name:       "Rings",
+		MinPlayers: 1,
+		MaxPlayers: 8,
+		BoardSizes: OddSizes(rules.BoardSizeSmall, rules.BoardSizeXXLarge),
+	}
+}
+
+func (m RingsHazardsMap) SetupBoard(initialBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if err := m.Meta().Validate(initialBoardState); err != nil {
+		return err
+	}
+
+	if err := (StandardMap{}).SetupBoard(initialBoardState, settings, editor); err != nil {
+		return err
+	}
+
+	center := rules.Point{X: (initialBoardState.Width - 1) / 2, Y: (initialBoardState.Height - 1) / 2}
+	var ringPoints []rules.Point
+	for x := 0; x < initialBoardState.Width; x++ {
+		for y := 0; y < initialBoardState.Height; y++ {
+			distance := maxInt(absInt(x-center.X), absInt(y-center.Y))
+			if distance%ringsHazardsSpacing == ringsHazardsSpacing-1 {
+				ringPoints = append(ringPoints, rules.Point{X: x, Y: y})
+			}
+		}
+	}
+
+	// Leave the starting positions clear so no snake begins the game in a hazard
+	for _, p := range editor.FilterUnoccupiedPoints(ringPoints, true, false, true) {
+		editor.AddHazard(p)
+	}
+
+	return nil
+}
+
+func (m RingsHazardsMap) PreUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	return nil
+}
+
+func (m RingsHazardsMap) PostUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	return StandardMap{}.PostUpdateBoard(lastBoardState, settings, editor)
+}
+
+// SpiralHazardsMap uses the standard board setup and grows a spiral of hazards outwards from the center of the board.
+type SpiralHazardsMap struct{}
+
+// spiralHazardsEveryNTurns is the number of turns between each new hazard in the spiral.
+const spiralHazardsEveryNTurns = 3
+
+func (m SpiralHazardsMap) ID() string {
+	return "hz_spiral"
+}
+
+func (m SpiralHazardsMap) Meta() Metadata {
+	return Metadata{
+		Name:       "Spiral",
+		MinPlayers: 1,
+		MaxPlayers: 8,
+		BoardSizes: OddSizes(rules.BoardSizeSmall, rules.BoardSizeXXLarge),
+	}
+}
+
+func (m SpiralHazardsMap) SetupBoard(initialBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if err := m.Meta().Validate(initialBoardState); err != nil {
+		return err
+	}
+
+	return StandardMap{}.SetupBoard(initialBoardState, settings, editor)
+}
+
+func (m SpiralHazardsMap) PreUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	return nil
+}
+
+func (m SpiralHazardsMap) PostUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if err := (StandardMap{}).PostUpdateBoard(lastBoardState, settings, editor); err != nil {
+		return err
+	}
+
+	turn := lastBoardState.Turn + 1
+	if turn%spiralHazardsEveryNTurns != 0 {
+		return nil
+	}
+
+	center := rules.Point{X: (lastBoardState.Width - 1) / 2, Y: (lastBoardState.Height - 1) / 2}
+	p := spiralPoint(center, turn/spiralHazardsEveryNTurns-1)
+	if p.X >= 0 && p.X < lastBoardState.Width && p.Y >= 0 && p.Y < lastBoardState.Height {
+		editor.AddHazard(p)
+	}
+
+	return nil
+}
+
+// spiralPoint returns the nth point of a square spiral starting at center,
+// turning anticlockwise: right 1, up 1, left 2, down 2, right 3...
+func spiralPoint(center rules.Point, n int) rules.Point {
+	directions := []rules.Point{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 0, Y: -1}}
+	p := center
+	for leg := 0; n > 0; leg++ {
+		stepLength := leg/2 + 1
+		direction := directions[leg%4]
+		for step := 0; step < stepLength && n > 0; step++ {
+			p.X += direction.X
+			p.Y += direction.Y
+			n--
+		}
+	}
+	return p
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,85 @@
+package maps_test
+
+import (
+	"testing"
+
+	"rules"
+	"rules/maps"
+	"rules/settings"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingsHazardsMapInterface(t *testing.T) {
+	var _ maps.GameMap = maps.RingsHazardsMap{}
+}
+
+func TestRingsHazardsMapSetupBoard(t *testing.T) {
+	m := maps.RingsHazardsMap{}
+	s := settings.Settings{}.WithRand(rules.MinRand)
+
+	boardState := rules.NewBoardState(11, 11).WithSnakes(generateSnakes(8))
+	err := m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.NoError(t, err)
+	require.NotEmpty(t, boardState.Hazards)
+
+	hazards := map[rules.Point]bool{}
+	for _, p := range boardState.Hazards {
+		hazards[p] = true
+	}
+	require.True(t, hazards[rules.Point{X: 5, Y: 3}])
+	require.True(t, hazards[rules.Point{X: 3, Y: 7}])
+	require.True(t, hazards[rules.Point{X: 0, Y: 0}])
+	require.False(t, hazards[rules.Point{X: 5, Y: 5}])
+	require.False(t, hazards[rules.Point{X: 4, Y: 5}])
+	for _, snake := range boardState.Snakes {
+		require.False(t, hazards[snake.Body[0]], "snake %s starts in a hazard", snake.ID)
+	}
+	for _, food := range boardState.Food {
+		require.False(t, hazards[food], "food %v placed in a hazard", food)
+	}
+
+	boardState = rules.NewBoardState(10, 10)
+	err = m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.Error(t, err)
+}
+
+func TestSpiralHazardsMapInterface(t *testing.T) {
+	var _ maps.GameMap = maps.SpiralHazardsMap{}
+}
+
+func TestSpiralHazardsMapUpdateBoard(t *testing.T) {
+	m := maps.SpiralHazardsMap{}
+	s := settings.Settings{}.WithRand(rules.MinRand)
+
+	boardState := rules.NewBoardState(7, 7)
+	for turn := 0; turn < 15; turn++ {
+		boardState.Turn = turn
+		nextBoardState, err := maps.PostUpdateBoard(m, boardState, s)
+		require.NoError(t, err)
+		boardState = nextBoardState
+	}
+
+	require.Equal(t, []rules.Point{
+		{X: 3, Y: 3},
+		{X: 4, Y: 3},
+		{X: 4, Y: 4},
+		{X: 3, Y: 4},
+		{X: 2, Y: 4},
+	}, boardState.Hazards)
+}
+
+func TestSpiralHazardsMapOffBoard(t *testing.T) {
+	m := maps.SpiralHazardsMap{}
+	s := settings.Settings{}.WithRand(rules.MinRand)
+
+	// The spiral eventually leaves the board, at which point no more hazards are added
+	boardState := rules.NewBoardState(7, 7)
+	for turn := 0; turn < 3*10*10; turn++ {
+		boardState.Turn = turn
+		nextBoardState, err := maps.PostUpdateBoard(m, boardState, s)
+		require.NoError(t, err)
+		boardState = nextBoardState
+	}
+	require.Len(t, boardState.Hazards, 7*7)
+}
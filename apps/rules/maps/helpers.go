@@ -50,6 +50,7 @@ type StubMap struct {
 	Id             string
 	SnakePositions map[string]rules.Point
 	Food           []rules.Point
+	Hazards        []rules.Point
 	Error          error
 }
 
@@ -72,6 +73,9 @@ func (m StubMap) SetupBoard(initialBoardState *rules.BoardState, settings settin
 	for _, food := range m.Food {
 		editor.AddFood(food)
 	}
+	for _, hazard := range m.Hazards {
+		editor.AddHazard(hazard)
+	}
 	return nil
 }
 
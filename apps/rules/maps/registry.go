@@ -0,0 +1,55 @@
+package maps
+
+import (
+	"fmt"
+	"sort"
+
+	"rules"
+)
+
+// MapRegistry is a mapping of map IDs to game maps.
+type MapRegistry map[string]GameMap
+
+var globalRegistry = MapRegistry{}
+
+// RegisterMap adds a map to the registry.
+// If a map has already been registered with the same ID this will panic.
+func (registry MapRegistry) RegisterMap(id string, m GameMap) {
+	if _, ok := registry[id]; ok {
+		panic(fmt.Sprintf("map '%s' has already been registered", id))
+	}
+	registry[id] = m
+}
+
+// List returns all registered map IDs in alphabetical order.
+func (registry MapRegistry) List() []string {
+	var keys []string
+	for k := range registry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetMap returns the map associated with the given ID.
+func (registry MapRegistry) GetMap(id string) (GameMap, error) {
+	if m, ok := registry[id]; ok {
+		return m, nil
+	}
+	return nil, rules.ErrorMapNotFound
+}
+
+// RegisterMap adds a map to the global registry.
+func RegisterMap(id string, m GameMap) {
+	globalRegistry.RegisterMap(id, m)
+}
+
+// GetMap returns the map associated with the given ID from the global registry.
+func GetMap(id string) (GameMap, error) {
+	return globalRegistry.GetMap(id)
+}
+
+// List returns all map IDs in the global registry in alphabetical order.
+func List() []string {
+	return globalRegistry.List()
+}
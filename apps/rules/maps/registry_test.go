@@ -0,0 +1,41 @@
+package maps_test
+
+import (
+	"testing"
+
+	"rules"
+	"rules/maps"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := maps.MapRegistry{}
+	registry.RegisterMap("stub", maps.StubMap{Id: "stub"})
+	registry.RegisterMap("another", maps.StubMap{Id: "another"})
+
+	require.Equal(t, []string{"another", "stub"}, registry.List())
+
+	m, err := registry.GetMap("stub")
+	require.NoError(t, err)
+	require.Equal(t, "stub", m.ID())
+
+	_, err = registry.GetMap("missing")
+	require.Equal(t, rules.ErrorMapNotFound, err)
+
+	require.Panics(t, func() {
+		registry.RegisterMap("stub", maps.StubMap{Id: "stub"})
+	})
+}
+
+func TestGlobalRegistry(t *testing.T) {
+	for _, id := range maps.List() {
+		m, err := maps.GetMap(id)
+		require.NoError(t, err)
+		require.Equal(t, id, m.ID(), "map %s is registered under the wrong ID", id)
+	}
+
+	for _, id := range []string{"standard", "empty", "arcade_maze", "hz_rings", "hz_spiral", "snail_mode", "corner_food"} {
+		require.Contains(t, maps.List(), id)
+	}
+}
@@ -0,0 +1,131 @@
+package maps
+
+import (
+	"fmt"
+	"sort"
+
+	"rules"
+	"rules/settings"
+)
+
+// SnailModeMap uses the standard board setup, but snakes leave a trail of hazards
+// behind their tails. Each trail hazard lasts for as many turns as the snake was long.
+type SnailModeMap struct{}
+
+func init() {
+	globalRegistry.RegisterMap("snail_mode", SnailModeMap{})
+}
+
+func (m SnailModeMap) ID() string {
+	return "snail_mode"
+}
+
+func (m SnailModeMap) Meta() Metadata {
+	return Metadata{
+		Name:       "Snail Mode",
+		MinPlayers: 1,
+		MaxPlayers: 8,
+		BoardSizes: OddSizes(rules.BoardSizeSmall, rules.BoardSizeXXLarge),
+	}
+}
+
+func (m SnailModeMap) SetupBoard(initialBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if err := m.Meta().Validate(initialBoardState); err != nil {
+		return err
+	}
+
+	if err := (StandardMap{}).SetupBoard(initialBoardState, settings, editor); err != nil {
+		return err
+	}
+
+	for id, body := range editor.SnakeBodies() {
+		storeSnailTail(editor, id, body)
+	}
+
+	return nil
+}
+
+func (m SnailModeMap) PreUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	return nil
+}
+
+func (m SnailModeMap) PostUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if err := (StandardMap{}).PostUpdateBoard(lastBoardState, settings, editor); err != nil {
+		return err
+	}
+
+	// Fade out the existing trail, PointState holds the number of turns each trail hazard has left
+	// Points are visited in a fixed order so that hazard order doesn't depend on map iteration
+	pointState := editor.PointState()
+	trail := make([]rules.Point, 0, len(pointState))
+	for p := range pointState {
+		trail = append(trail, p)
+	}
+	sort.Slice(trail, func(i, j int) bool {
+		if trail[i].X != trail[j].X {
+			return trail[i].X < trail[j].X
+		}
+		return trail[i].Y < trail[j].Y
+	})
+	for _, p := range trail {
+		turnsLeft := pointState[p]
+		if turnsLeft <= 1 {
+			delete(pointState, p)
+			editor.RemoveHazard(p)
+		} else {
+			pointState[p] = turnsLeft - 1
+		}
+	}
+
+	// Leave a hazard where each snake's tail was last turn, if it has since moved away
+	for _, snake := range lastBoardState.Snakes {
+		if snake.EliminatedCause != rules.NotEliminated || len(snake.Body) == 0 {
+			continue
+		}
+
+		prevTail, ok := loadSnailTail(editor, snake.ID)
+		if ok && !containsPoint(snake.Body, prevTail) {
+			if _, isTrail := pointState[prevTail]; !isTrail {
+				editor.AddHazard(prevTail)
+			}
+			pointState[prevTail] = len(snake.Body)
+		}
+
+		storeSnailTail(editor, snake.ID, snake.Body)
+	}
+
+	return nil
+}
+
+func snailTailKey(snakeID string) string {
+	return "snail_mode.tail." + snakeID
+}
+
+func storeSnailTail(editor Editor, snakeID string, body []rules.Point) {
+	if len(body) == 0 {
+		return
+	}
+	tail := body[len(body)-1]
+	editor.GameState()[snailTailKey(snakeID)] = fmt.Sprintf("%d,%d", tail.X, tail.Y)
+}
+
+func loadSnailTail(editor Editor, snakeID string) (rules.Point, bool) {
+	var p rules.Point
+	value, ok := editor.GameState()[snailTailKey(snakeID)]
+	if !ok {
+		return p, false
+	}
+	if _, err := fmt.Sscanf(value, "%d,%d", &p.X, &p.Y); err != nil {
+		return p, false
+	}
+	return p, true
+}
+
+func containsPoint(points []rules.Point, target rules.Point) bool {
+	for _, p := range points {
+		if p == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package maps_test
+
+import (
+	"testing"
+
+	"rules"
+	"rules/maps"
+	"rules/settings"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnailModeMapInterface(t *testing.T) {
+	var _ maps.GameMap = maps.SnailModeMap{}
+}
+
+func TestSnailModeMapTrail(t *testing.T) {
+	m := maps.SnailModeMap{}
+	s := settings.Settings{}.WithRand(rules.MinRand)
+
+	boardState := rules.NewBoardState(7, 7).WithSnakes(generateSnakes(1))
+	err := m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.NoError(t, err)
+	require.Equal(t, []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 1}}, boardState.Snakes[0].Body)
+	boardState.Food = []rules.Point{}
+
+	moves := [][]rules.Point{
+		// Stacked tail hasn't moved yet, so no trail is left
+		{{X: 1, Y: 2}, {X: 1, Y: 1}, {X: 1, Y: 1}},
+		{{X: 1, Y: 3}, {X: 1, Y: 2}, {X: 1, Y: 1}},
+		// Tail leaves (1, 1)
+		{{X: 1, Y: 4}, {X: 1, Y: 3}, {X: 1, Y: 2}},
+		{{X: 1, Y: 5}, {X: 1, Y: 4}, {X: 1, Y: 3}},
+		{{X: 2, Y: 5}, {X: 1, Y: 5}, {X: 1, Y: 4}},
+		{{X: 3, Y: 5}, {X: 2, Y: 5}, {X: 1, Y: 5}},
+	}
+	expectedHazards := [][]rules.Point{
+		{},
+		{},
+		{{X: 1, Y: 1}},
+		{{X: 1, Y: 1}, {X: 1, Y: 2}},
+		{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}},
+		// (1, 1) fades after 3 turns since the snake is length 3
+		{{X: 1, Y: 2}, {X: 1, Y: 3}, {X: 1, Y: 4}},
+	}
+
+	for turn, body := range moves {
+		boardState.Turn = turn
+		boardState.Snakes[0].Body = body
+
+		nextBoardState, err := maps.PostUpdateBoard(m, boardState, s)
+		require.NoError(t, err)
+		require.ElementsMatch(t, expectedHazards[turn], nextBoardState.Hazards, "turn %d", turn)
+		boardState = nextBoardState
+	}
+}
+
+func TestSnailModeMapIgnoresEliminatedSnakes(t *testing.T) {
+	m := maps.SnailModeMap{}
+	s := settings.Settings{}.WithRand(rules.MinRand)
+
+	boardState := rules.NewBoardState(7, 7).WithSnakes(generateSnakes(1))
+	err := m.SetupBoard(boardState, s, maps.NewBoardStateEditor(boardState))
+	require.NoError(t, err)
+
+	boardState.Snakes[0].Body = []rules.Point{{X: 1, Y: 4}, {X: 1, Y: 3}, {X: 1, Y: 2}}
+	boardState.Snakes[0].EliminatedCause = rules.EliminatedByOutOfHealth
+	nextBoardState, err := maps.PostUpdateBoard(m, boardState, s)
+	require.NoError(t, err)
+	require.Empty(t, nextBoardState.Hazards)
+}
@@ -7,6 +7,10 @@ import (
 
 type StandardMap struct{}
 
+func init() {
+	globalRegistry.RegisterMap("standard", StandardMap{})
+}
+
 func (m StandardMap) ID() string {
 	return "standard"
 }
@@ -38,8 +42,10 @@ func (m StandardMap) SetupBoard(initialBoardState *rules.BoardState, settings se
 	}
 
 	// Copy food from temp board state
-	for _, food := range tempBoardState.Food {
-		editor.AddFood(food)
+	if !isFoodDisabled(settings) {
+		for _, food := range tempBoardState.Food {
+			editor.AddFood(food)
+		}
 	}
 
 	// Copy snakes from temp board state
@@ -55,9 +61,13 @@ func (m StandardMap) PreUpdateBoard(lastBoardState *rules.BoardState, settings s
 }
 
 func (m StandardMap) PostUpdateBoard(lastBoardState *rules.BoardState, settings settings.Settings, editor Editor) error {
+	if isFoodDisabled(settings) {
+		return nil
+	}
+
 	rand := settings.GetRand(lastBoardState.Turn)
 
-	foodNeeded := checkFoodNeedingPlacement(rand, settings)
+	foodNeeded := checkFoodNeedingPlacement(rand, settings, lastBoardState)
 	if foodNeeded > 0 {
 		placeFoodRandomly(rand, lastBoardState, editor, foodNeeded)
 	}
@@ -65,9 +75,24 @@ func (m StandardMap) PostUpdateBoard(lastBoardState *rules.BoardState, settings
 	return nil
 }
 
-func checkFoodNeedingPlacement(rand rules.Rand, settings settings.Settings) int {
+// isFoodDisabled reports whether the game type being played never spawns food.
+// Constrictor snakes grow every turn instead of eating.
+func isFoodDisabled(settings settings.Settings) bool {
+	return settings.String(rules.ParamGameType, "") == rules.GameTypeConstrictor
+}
+
+// checkFoodNeedingPlacement tops the board up to the minimum food first,
+// and only rolls for a random spawn once that minimum is met.
+func checkFoodNeedingPlacement(rand rules.Rand, settings settings.Settings, lastBoardState *rules.BoardState) int {
+	minFood := settings.Int(rules.ParamMinimumFood, 0)
 	foodSpawnChance := settings.Int(rules.ParamFoodSpawnChance, 0)
+	return countFoodNeeded(rand, minFood, foodSpawnChance, len(lastBoardState.Food))
+}
 
+func countFoodNeeded(rand rules.Rand, minFood, foodSpawnChance, numCurrentFood int) int {
+	if numCurrentFood < minFood {
+		return minFood - numCurrentFood
+	}
 	if foodSpawnChance > 0 && (100-rand.Intn(100)) < foodSpawnChance {
 		return 1
 	}
@@ -76,7 +101,7 @@ func checkFoodNeedingPlacement(rand rules.Rand, settings settings.Settings) int
 }
 
 func placeFoodRandomly(rand rules.Rand, b *rules.BoardState, editor Editor, n int) {
-	unoccupiedPoints := rules.GetUnoccupiedPoints(b, false)
+	unoccupiedPoints := rules.GetUnoccupiedPoints(b, false, false)
 	placeFoodRandomlyAtPositions(rand, editor, n, unoccupiedPoints)
 }
 
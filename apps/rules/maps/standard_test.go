@@ -161,6 +161,55 @@ func TestStandardMapUpdateBoard(t *testing.T) {
 			rules.MaxRand,
 			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}),
 		},
+		{
+			"empty MinimumFood",
+			rules.NewBoardState(2, 2),
+			settings.NewSettingsWithParams(rules.ParamMinimumFood, "2"),
+			rules.MinRand,
+			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}}),
+		},
+		{
+			"not empty MinimumFood",
+			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}}),
+			settings.NewSettingsWithParams(rules.ParamMinimumFood, "2"),
+			rules.MinRand,
+			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}}),
+		},
+		{
+			"MinimumFood met FoodSpawnChance inactive",
+			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}}),
+			settings.NewSettingsWithParams(rules.ParamMinimumFood, "2", rules.ParamFoodSpawnChance, "50"),
+			rules.MinRand,
+			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}}),
+		},
+		{
+			"MinimumFood met FoodSpawnChance active",
+			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}}),
+			settings.NewSettingsWithParams(rules.ParamMinimumFood, "2", rules.ParamFoodSpawnChance, "50"),
+			rules.MaxRand,
+			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}),
+		},
+		{
+			"MinimumFood no room",
+			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 0}}),
+			settings.NewSettingsWithParams(rules.ParamMinimumFood, "10"),
+			rules.MinRand,
+			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 0}, {X: 1, Y: 1}}),
+		},
+		{
+			"constrictor MinimumFood",
+			rules.NewBoardState(2, 2),
+			settings.NewSettingsWithParams(rules.ParamMinimumFood, "2", rules.ParamGameType, rules.GameTypeConstrictor),
+			rules.MinRand,
+			rules.NewBoardState(2, 2),
+		},
+		{
+			"constrictor FoodSpawnChance active",
+			rules.NewBoardState(2, 2),
+			settings.NewSettingsWithParams(rules.ParamFoodSpawnChance, "50", rules.ParamGameType, rules.GameTypeConstrictor),
+			rules.MaxRand,
+			rules.NewBoardState(2, 2),
+		},
 		{
 			"not empty FoodSpawnChance no room",
 			rules.NewBoardState(2, 2).WithFood([]rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 0}, {X: 1, Y: 1}}),
@@ -183,6 +232,20 @@ func TestStandardMapUpdateBoard(t *testing.T) {
 	}
 }
 
+func TestStandardMapSetupBoardConstrictor(t *testing.T) {
+	m := maps.StandardMap{}
+	initialBoardState := rules.NewBoardState(11, 11).WithSnakes(generateSnakes(2))
+	nextBoardState := rules.NewBoardState(11, 11)
+	editor := maps.NewBoardStateEditor(nextBoardState)
+	settings := settings.NewSettingsWithParams(rules.ParamGameType, rules.GameTypeConstrictor).WithRand(rules.MinRand)
+
+	err := m.SetupBoard(initialBoardState, settings, editor)
+
+	require.NoError(t, err)
+	require.Len(t, nextBoardState.Snakes, 2)
+	require.Equal(t, []rules.Point{}, nextBoardState.Food)
+}
+
 func generateSnakes(n int) []rules.Snake {
 	var snakes []rules.Snake
 	for i := 0; i < n; i++ {
@@ -0,0 +1,44 @@
+package rulesets
+
+import (
+	"rules"
+	"rules/settings"
+)
+
+var constrictorRulesetStages = []string{
+	StageGameOverStandard,
+	StageGameOverMaxTurns,
+	StageMovementStandard,
+	StageStarvationStandard,
+	StageHazardDamageStandard,
+	StageFeedSnakesConstrictor,
+	StageEliminationStandard,
+}
+
+// GrowSnakesConstrictor replaces feeding in constrictor games: every living
+// snake is kept at full health and grows by one segment each turn.
+func GrowSnakesConstrictor(b *rules.BoardState, settings settings.Settings, moves []SnakeMove) (bool, error) {
+	for i := 0; i < len(b.Snakes); i++ {
+		snake := &b.Snakes[i]
+		if snake.EliminatedCause != rules.NotEliminated {
+			continue
+		}
+		if len(snake.Body) == 0 {
+			return false, rules.ErrorZeroLengthSnake
+		}
+
+		snake.Health = rules.SnakeMaxHealth
+
+		// A stacked tail means the snake will already grow on its next move,
+		// so only add a segment once the tail has started to unstack.
+		if len(snake.Body) < 2 {
+			continue
+		}
+		tail := snake.Body[len(snake.Body)-1]
+		subTail := snake.Body[len(snake.Body)-2]
+		if tail != subTail {
+			growSnake(snake)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,112 @@
+package rulesets
+
+import (
+	"rules"
+	"rules/settings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getConstrictorRuleset(settings settings.Settings) Ruleset {
+	return NewRulesetBuilder().WithSettings(settings).NamedRuleset(rules.GameTypeConstrictor)
+}
+
+func TestConstrictorName(t *testing.T) {
+	r := getConstrictorRuleset(settings.Settings{})
+	require.Equal(t, "constrictor", r.Name())
+}
+
+func TestGrowSnakesConstrictor(t *testing.T) {
+	b := &rules.BoardState{
+		Snakes: []rules.Snake{
+			{
+				ID:     "stacked",
+				Body:   []rules.Point{{X: 1, Y: 2}, {X: 1, Y: 1}, {X: 1, Y: 1}},
+				Health: 50,
+			},
+			{
+				ID:     "unstacked",
+				Body:   []rules.Point{{X: 3, Y: 3}, {X: 3, Y: 2}, {X: 3, Y: 1}},
+				Health: 99,
+			},
+			{
+				ID:              "eliminated",
+				Body:            []rules.Point{{X: 5, Y: 3}, {X: 5, Y: 2}, {X: 5, Y: 1}},
+				Health:          10,
+				EliminatedCause: rules.EliminatedByCollision,
+			},
+		},
+	}
+
+	_, err := GrowSnakesConstrictor(b, settings.Settings{}, mockSnakeMoves())
+	require.NoError(t, err)
+	require.Equal(t, rules.SnakeMaxHealth, b.Snakes[0].Health)
+	require.Equal(t, []rules.Point{{X: 1, Y: 2}, {X: 1, Y: 1}, {X: 1, Y: 1}}, b.Snakes[0].Body)
+	require.Equal(t, rules.SnakeMaxHealth, b.Snakes[1].Health)
+	require.Equal(t, []rules.Point{{X: 3, Y: 3}, {X: 3, Y: 2}, {X: 3, Y: 1}, {X: 3, Y: 1}}, b.Snakes[1].Body)
+	require.Equal(t, 10, b.Snakes[2].Health)
+	require.Len(t, b.Snakes[2].Body, 3)
+
+	b.Snakes[1].Body = []rules.Point{}
+	_, err = GrowSnakesConstrictor(b, settings.Settings{}, mockSnakeMoves())
+	require.Equal(t, rules.ErrorZeroLengthSnake, err)
+}
+
+// Checks that snakes grow every turn, stay at full health and ignore food
+var constrictorCaseGrowEveryTurn = gameTestCase{
+	"Constrictor Case Grow Every Turn",
+	&rules.BoardState{
+		Width:  10,
+		Height: 10,
+		Snakes: []rules.Snake{
+			{
+				ID:     "one",
+				Body:   []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}},
+				Health: 100,
+			},
+			{
+				ID:     "two",
+				Body:   []rules.Point{{X: 3, Y: 4}, {X: 3, Y: 3}, {X: 3, Y: 3}},
+				Health: 100,
+			},
+		},
+		Food: []rules.Point{{X: 1, Y: 0}},
+	},
+	[]SnakeMove{
+		{ID: "one", Move: rules.MoveDown},
+		{ID: "two", Move: rules.MoveUp},
+	},
+	nil,
+	&rules.BoardState{
+		Width:  10,
+		Height: 10,
+		Snakes: []rules.Snake{
+			{
+				ID:     "one",
+				Body:   []rules.Point{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 2}},
+				Health: 100,
+			},
+			{
+				ID:     "two",
+				Body:   []rules.Point{{X: 3, Y: 5}, {X: 3, Y: 4}, {X: 3, Y: 3}, {X: 3, Y: 3}},
+				Health: 100,
+			},
+		},
+		Food: []rules.Point{{X: 1, Y: 0}},
+	},
+}
+
+func TestConstrictorCreateNextBoardState(t *testing.T) {
+	cases := []gameTestCase{
+		standardCaseErrNoMoveFound,
+		standardCaseErrZeroLengthSnake,
+		constrictorCaseGrowEveryTurn,
+	}
+	r := getConstrictorRuleset(settings.Settings{})
+	for _, gc := range cases {
+		gc.requireValidNextState(t, r)
+		// also test a pipeline with the same settings
+		gc.requireValidNextState(t, NewRulesetBuilder().PipelineRuleset(rules.GameTypeConstrictor, NewPipeline(constrictorRulesetStages...)))
+	}
+}
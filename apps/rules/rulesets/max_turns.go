@@ -0,0 +1,100 @@
+package rulesets
+
+import (
+	"sort"
+	"strings"
+
+	"rules"
+	"rules/settings"
+)
+
+// DefaultTieBreak is the order of tie-break criteria used when the tieBreak setting isn't set.
+const DefaultTieBreak = rules.TieBreakLength + "," + rules.TieBreakHealth + "," + rules.TieBreakKills
+
+// GameOverMaxTurns ends the game once the maxTurns setting has been reached.
+// A maxTurns of zero means there is no limit.
+func GameOverMaxTurns(b *rules.BoardState, settings settings.Settings, moves []SnakeMove) (bool, error) {
+	maxTurns := settings.Int(rules.ParamMaxTurns, 0)
+	return maxTurns > 0 && b.Turn >= maxTurns, nil
+}
+
+// RankSurvivors orders the snakes that haven't been eliminated using the tieBreak setting.
+// Each element of the result holds the IDs of snakes sharing that rank, best first,
+// so a game has a single winner only if the first rank holds one snake.
+func RankSurvivors(b *rules.BoardState, settings settings.Settings) [][]string {
+	var tieBreaks []string
+	for _, tieBreak := range strings.Split(settings.String(rules.ParamTieBreak, DefaultTieBreak), ",") {
+		if tieBreak = strings.TrimSpace(tieBreak); tieBreak != "" {
+			tieBreaks = append(tieBreaks, tieBreak)
+		}
+	}
+
+	kills := map[string]int{}
+	var survivors []rules.Snake
+	for _, snake := range b.Snakes {
+		if snake.EliminatedCause == rules.NotEliminated {
+			survivors = append(survivors, snake)
+		} else if snake.EliminatedBy != "" {
+			kills[snake.EliminatedBy]++
+		}
+	}
+
+	// compare returns a positive number if a ranks ahead of b, negative if behind and zero if they're tied
+	compare := func(a, b rules.Snake) int {
+		for _, tieBreak := range tieBreaks {
+			var diff int
+			switch tieBreak {
+			case rules.TieBreakLength:
+				diff = len(a.Body) - len(b.Body)
+			case rules.TieBreakHealth:
+				diff = a.Health - b.Health
+			case rules.TieBreakKills:
+				diff = kills[a.ID] - kills[b.ID]
+			}
+			if diff != 0 {
+				return diff
+			}
+		}
+		return 0
+	}
+
+	sort.SliceStable(survivors, func(i, j int) bool {
+		return compare(survivors[i], survivors[j]) > 0
+	})
+
+	ranks := [][]string{}
+	for i, snake := range survivors {
+		if i > 0 && compare(survivors[i-1], snake) == 0 {
+			ranks[len(ranks)-1] = append(ranks[len(ranks)-1], snake.ID)
+		} else {
+			ranks = append(ranks, []string{snake.ID})
+		}
+	}
+	return ranks
+}
+
+// RankSnakes orders every snake in the game, best first. Snakes that haven't been eliminated
+// are ranked with RankSurvivors, followed by eliminated snakes with the most recently eliminated first.
+// Snakes eliminated on the same turn share a rank.
+func RankSnakes(b *rules.BoardState, settings settings.Settings) [][]string {
+	ranks := RankSurvivors(b, settings)
+
+	var eliminated []rules.Snake
+	for _, snake := range b.Snakes {
+		if snake.EliminatedCause != rules.NotEliminated {
+			eliminated = append(eliminated, snake)
+		}
+	}
+	sort.SliceStable(eliminated, func(i, j int) bool {
+		return eliminated[i].EliminatedOnTurn > eliminated[j].EliminatedOnTurn
+	})
+
+	for i, snake := range eliminated {
+		if i > 0 && eliminated[i-1].EliminatedOnTurn == snake.EliminatedOnTurn {
+			ranks[len(ranks)-1] = append(ranks[len(ranks)-1], snake.ID)
+		} else {
+			ranks = append(ranks, []string{snake.ID})
+		}
+	}
+	return ranks
+}
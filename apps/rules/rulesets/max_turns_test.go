@@ -0,0 +1,97 @@
+package rulesets
+
+import (
+	"testing"
+
+	"rules"
+	"rules/settings"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameOverMaxTurns(t *testing.T) {
+	tests := []struct {
+		maxTurns string
+		turn     int
+		expected bool
+	}{
+		{"0", 0, false},
+		{"0", 1000, false},
+		{"10", 0, false},
+		{"10", 9, false},
+		{"10", 10, true},
+		{"10", 11, true},
+	}
+
+	for _, test := range tests {
+		b := rules.NewBoardState(11, 11)
+		b.Turn = test.turn
+		gameOver, err := GameOverMaxTurns(b, settings.NewSettingsWithParams(rules.ParamMaxTurns, test.maxTurns), mockSnakeMoves())
+		require.NoError(t, err)
+		require.Equal(t, test.expected, gameOver)
+	}
+}
+
+func TestNamedRulesetMaxTurns(t *testing.T) {
+	snakes := []rules.Snake{
+		{ID: "one", Body: []rules.Point{{X: 1, Y: 1}}, Health: 100},
+		{ID: "two", Body: []rules.Point{{X: 5, Y: 5}}, Health: 100},
+	}
+	for _, name := range []string{rules.GameTypeStandard, rules.GameTypeSolo, rules.GameTypeWrapped, rules.GameTypeRoyale, rules.GameTypeConstrictor, rules.GameTypeSquad} {
+		r := NewRulesetBuilder().WithParams(map[string]string{
+			rules.ParamMaxTurns:          "5",
+			rules.ParamShrinkEveryNTurns: "25",
+		}).NamedRuleset(name)
+
+		b := rules.NewBoardState(11, 11).WithSnakes(snakes)
+		b.Turn = 5
+		gameOver, _, err := r.Execute(b, nil)
+		require.NoError(t, err)
+		require.True(t, gameOver, name)
+	}
+}
+
+func TestRankSurvivors(t *testing.T) {
+	b := rules.NewBoardState(11, 11).WithSnakes([]rules.Snake{
+		{ID: "short", Health: 100, Body: []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 2}}},
+		{ID: "long", Health: 50, Body: []rules.Point{{X: 3, Y: 1}, {X: 3, Y: 2}, {X: 3, Y: 3}}},
+		{ID: "killer", Health: 50, Body: []rules.Point{{X: 5, Y: 1}, {X: 5, Y: 2}, {X: 5, Y: 3}}},
+		{ID: "victim", Health: 100, Body: []rules.Point{{X: 7, Y: 1}}, EliminatedCause: rules.EliminatedByCollision, EliminatedBy: "killer"},
+	})
+
+	tests := []struct {
+		tieBreak string
+		expected [][]string
+	}{
+		{DefaultTieBreak, [][]string{{"killer"}, {"long"}, {"short"}}},
+		{"length,health", [][]string{{"long", "killer"}, {"short"}}},
+		{"health", [][]string{{"short"}, {"long", "killer"}}},
+		{"kills,health", [][]string{{"killer"}, {"short"}, {"long"}}},
+		{"", [][]string{{"short", "long", "killer"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tieBreak, func(t *testing.T) {
+			ranks := RankSurvivors(b, settings.NewSettingsWithParams(rules.ParamTieBreak, test.tieBreak))
+			require.Equal(t, test.expected, ranks)
+		})
+	}
+
+	// The default tie-break is used when the setting is missing
+	require.Equal(t, [][]string{{"killer"}, {"long"}, {"short"}}, RankSurvivors(b, settings.Settings{}))
+}
+
+func TestRankSnakes(t *testing.T) {
+	b := rules.NewBoardState(11, 11).WithSnakes([]rules.Snake{
+		{ID: "first-out", Body: []rules.Point{{X: 1, Y: 1}}, EliminatedCause: rules.EliminatedByOutOfBounds, EliminatedOnTurn: 3},
+		{ID: "head-to-head-1", Body: []rules.Point{{X: 3, Y: 1}}, EliminatedCause: rules.EliminatedByHeadToHeadCollision, EliminatedBy: "head-to-head-2", EliminatedOnTurn: 8},
+		{ID: "survivor", Health: 80, Body: []rules.Point{{X: 5, Y: 1}, {X: 5, Y: 2}}},
+		{ID: "head-to-head-2", Body: []rules.Point{{X: 3, Y: 1}}, EliminatedCause: rules.EliminatedByHeadToHeadCollision, EliminatedBy: "head-to-head-1", EliminatedOnTurn: 8},
+		{ID: "longest", Health: 20, Body: []rules.Point{{X: 7, Y: 1}, {X: 7, Y: 2}, {X: 7, Y: 3}}},
+	})
+
+	ranks := RankSnakes(b, settings.Settings{})
+	require.Equal(t, [][]string{{"longest"}, {"survivor"}, {"head-to-head-1", "head-to-head-2"}, {"first-out"}}, ranks)
+
+	require.Equal(t, [][]string{}, RankSnakes(rules.NewBoardState(11, 11), settings.Settings{}))
+}
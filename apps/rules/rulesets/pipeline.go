@@ -7,14 +7,26 @@ import (
 )
 
 const (
-	StageSpawnFoodStandard   = "spawn_food.standard"
-	StageGameOverStandard    = "game_over.standard"
-	StageStarvationStandard  = "starvation.standard"
-	StageFeedSnakesStandard  = "feed_snakes.standard"
-	StageMovementStandard    = "movement.standard"
-	StageEliminationStandard = "elimination.standard"
+	StageSpawnFoodStandard    = "spawn_food.standard"
+	StageGameOverStandard     = "game_over.standard"
+	StageStarvationStandard   = "starvation.standard"
+	StageHazardDamageStandard = "hazard_damage.standard"
+	StageFeedSnakesStandard   = "feed_snakes.standard"
+	StageMovementStandard     = "movement.standard"
+	StageEliminationStandard  = "elimination.standard"
 
 	StageGameOverSoloSnake = "game_over.solo_snake"
+	StageGameOverMaxTurns  = "game_over.max_turns"
+
+	StageMovementWrapBoundaries = "movement.wrap_boundaries"
+
+	StageSpawnHazardsShrinkMap = "spawn_hazards.shrink_map"
+
+	StageFeedSnakesConstrictor = "feed_snakes.constrictor"
+
+	StageGameOverSquad                       = "game_over.squad"
+	StageEliminationResurrectSquadCollisions = "elimination.resurrect_squad_collisions"
+	StageModifySnakesShareAttributes         = "modify_snakes.share_attributes"
 )
 
 // globalRegistry is a global, default mapping of stage names to stage functions.
@@ -22,12 +34,24 @@ const (
 // Plugins that wish to extend the available game stages should call RegisterPipelineStageError
 // to add additional stages.
 var globalRegistry = StageRegistry{
-	StageGameOverSoloSnake:   GameOverSolo,
-	StageGameOverStandard:    GameOverStandard,
-	StageStarvationStandard:  ReduceSnakeHealthStandard,
-	StageFeedSnakesStandard:  FeedSnakesStandard,
-	StageEliminationStandard: EliminateSnakesStandard,
-	StageMovementStandard:    MoveSnakesStandard,
+	StageGameOverSoloSnake:    GameOverSolo,
+	StageGameOverStandard:     GameOverStandard,
+	StageGameOverMaxTurns:     GameOverMaxTurns,
+	StageStarvationStandard:   ReduceSnakeHealthStandard,
+	StageHazardDamageStandard: DamageHazardsStandard,
+	StageFeedSnakesStandard:   FeedSnakesStandard,
+	StageEliminationStandard:  EliminateSnakesStandard,
+	StageMovementStandard:     MoveSnakesStandard,
+
+	StageMovementWrapBoundaries: MoveSnakesWrapped,
+
+	StageSpawnHazardsShrinkMap: PopulateHazardsRoyale,
+
+	StageFeedSnakesConstrictor: GrowSnakesConstrictor,
+
+	StageGameOverSquad:                       GameOverSquad,
+	StageEliminationResurrectSquadCollisions: ResurrectSnakesSquad,
+	StageModifySnakesShareAttributes:         ShareAttributesSquad,
 }
 
 // Pipeline is an ordered sequences of game stages which are executed to produce the
@@ -0,0 +1,108 @@
+package rulesets
+
+import (
+	"strconv"
+
+	"rules"
+	"rules/settings"
+)
+
+var royaleRulesetStages = []string{
+	StageGameOverStandard,
+	StageGameOverMaxTurns,
+	StageMovementStandard,
+	StageStarvationStandard,
+	StageHazardDamageStandard,
+	StageFeedSnakesStandard,
+	StageEliminationStandard,
+	StageSpawnHazardsShrinkMap,
+}
+
+// Keys used to persist the bounds of the safe area in BoardState.GameState between turns.
+const (
+	royaleGameStateMinX = "royale.minX"
+	royaleGameStateMaxX = "royale.maxX"
+	royaleGameStateMinY = "royale.minY"
+	royaleGameStateMaxY = "royale.maxY"
+)
+
+// PopulateHazardsRoyale shrinks the safe area of the board by one row or column
+// every N turns, turning the cells it removes into hazards.
+// The side to shrink is chosen with settings.GetRand(turn), so a seeded game
+// always shrinks the same way.
+func PopulateHazardsRoyale(b *rules.BoardState, settings settings.Settings, moves []SnakeMove) (bool, error) {
+	if IsInitialization(b, settings, moves) {
+		return false, nil
+	}
+
+	shrinkEveryNTurns := settings.Int(rules.ParamShrinkEveryNTurns, 0)
+	if shrinkEveryNTurns < 1 {
+		return false, rules.RulesetError("royale game can't shrink more frequently than every turn")
+	}
+
+	// Royale uses the current turn to shrink the board, not the previous turn that's in the board state
+	turn := b.Turn + 1
+	if turn%shrinkEveryNTurns != 0 {
+		return false, nil
+	}
+
+	minX, maxX, minY, maxY := royaleSafeArea(b)
+	newMinX, newMaxX, newMinY, newMaxY := minX, maxX, minY, maxY
+	switch settings.GetRand(turn).Intn(4) {
+	case 0:
+		if newMinX < newMaxX {
+			newMinX++
+		}
+	case 1:
+		if newMaxX > newMinX {
+			newMaxX--
+		}
+	case 2:
+		if newMinY < newMaxY {
+			newMinY++
+		}
+	case 3:
+		if newMaxY > newMinY {
+			newMaxY--
+		}
+	}
+
+	// Only add hazards for the cells that just left the safe area
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			if x < newMinX || x > newMaxX || y < newMinY || y > newMaxY {
+				b.Hazards = append(b.Hazards, rules.Point{X: x, Y: y})
+			}
+		}
+	}
+
+	if b.GameState == nil {
+		b.GameState = map[string]string{}
+	}
+	b.GameState[royaleGameStateMinX] = strconv.Itoa(newMinX)
+	b.GameState[royaleGameStateMaxX] = strconv.Itoa(newMaxX)
+	b.GameState[royaleGameStateMinY] = strconv.Itoa(newMinY)
+	b.GameState[royaleGameStateMaxY] = strconv.Itoa(newMaxY)
+
+	return false, nil
+}
+
+// royaleSafeArea returns the bounds (inclusive) of the area that has not been
+// turned into hazards yet, defaulting to the whole board.
+func royaleSafeArea(b *rules.BoardState) (minX, maxX, minY, maxY int) {
+	minX = gameStateInt(b, royaleGameStateMinX, 0)
+	maxX = gameStateInt(b, royaleGameStateMaxX, b.Width-1)
+	minY = gameStateInt(b, royaleGameStateMinY, 0)
+	maxY = gameStateInt(b, royaleGameStateMaxY, b.Height-1)
+	return minX, maxX, minY, maxY
+}
+
+func gameStateInt(b *rules.BoardState, key string, defaultValue int) int {
+	if val, ok := b.GameState[key]; ok {
+		i, err := strconv.Atoi(val)
+		if err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
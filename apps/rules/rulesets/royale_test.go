@@ -0,0 +1,183 @@
+package rulesets
+
+import (
+	"fmt"
+	"rules"
+	"rules/settings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getRoyaleRuleset(settings settings.Settings) Ruleset {
+	return NewRulesetBuilder().WithSettings(settings).NamedRuleset(rules.GameTypeRoyale)
+}
+
+func TestRoyaleName(t *testing.T) {
+	r := getRoyaleRuleset(settings.Settings{})
+	require.Equal(t, "royale", r.Name())
+}
+
+func TestRoyaleDefaultSanity(t *testing.T) {
+	boardState := &rules.BoardState{
+		Width:  11,
+		Height: 11,
+		Snakes: []rules.Snake{
+			{ID: "1", Body: []rules.Point{{X: 0, Y: 0}}},
+			{ID: "2", Body: []rules.Point{{X: 0, Y: 1}}},
+		},
+	}
+	r := getRoyaleRuleset(settings.NewSettingsWithParams(rules.ParamShrinkEveryNTurns, "0"))
+	_, _, err := r.Execute(boardState, []SnakeMove{{"1", "right"}, {"2", "right"}})
+	require.Error(t, err)
+	require.Equal(t, rules.RulesetError("royale game can't shrink more frequently than every turn"), err)
+
+	r = getRoyaleRuleset(settings.NewSettingsWithParams(rules.ParamShrinkEveryNTurns, "1"))
+	_, boardState, err = r.Execute(boardState, []SnakeMove{{"1", "right"}, {"2", "right"}})
+	require.NoError(t, err)
+	require.Len(t, boardState.Hazards, 11)
+}
+
+func TestPopulateHazardsRoyale(t *testing.T) {
+	tests := []struct {
+		name              string
+		width, height     int
+		turn              int
+		shrinkEveryNTurns int
+		rand              rules.Rand
+		expectedHazards   []rules.Point
+	}{
+		{
+			name: "turn before first shrink", width: 3, height: 3, turn: 8, shrinkEveryNTurns: 10,
+			rand:            rules.MinRand,
+			expectedHazards: []rules.Point{},
+		},
+		{
+			name: "shrink left", width: 3, height: 3, turn: 9, shrinkEveryNTurns: 10,
+			rand:            rules.MinRand,
+			expectedHazards: []rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}},
+		},
+		{
+			name: "shrink bottom", width: 3, height: 3, turn: 19, shrinkEveryNTurns: 20,
+			rand:            rules.MaxRand,
+			expectedHazards: []rules.Point{{X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+		},
+		{
+			name: "every turn", width: 2, height: 4, turn: 0, shrinkEveryNTurns: 1,
+			rand:            rules.MinRand,
+			expectedHazards: []rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 0, Y: 3}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := rules.NewBoardState(test.width, test.height).WithTurn(test.turn)
+			s := settings.NewSettingsWithParams(rules.ParamShrinkEveryNTurns, fmt.Sprint(test.shrinkEveryNTurns)).WithRand(test.rand)
+
+			_, err := PopulateHazardsRoyale(b, s, mockSnakeMoves())
+			require.NoError(t, err)
+			require.Equal(t, test.expectedHazards, b.Hazards)
+		})
+	}
+}
+
+func TestPopulateHazardsRoyaleShrinksUntilOneCellRemains(t *testing.T) {
+	b := rules.NewBoardState(3, 3)
+	s := settings.NewSettingsWithParams(rules.ParamShrinkEveryNTurns, "1").WithRand(rules.MinRand)
+
+	// MinRand always shrinks from the left, so once the board is a single
+	// column the area can't shrink any further.
+	for turn := 0; turn < 5; turn++ {
+		_, err := PopulateHazardsRoyale(b.WithTurn(turn), s, mockSnakeMoves())
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []rules.Point{
+		{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2},
+		{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2},
+	}, b.Hazards)
+	require.Equal(t, "2", b.GameState[royaleGameStateMinX])
+	require.Equal(t, "2", b.GameState[royaleGameStateMaxX])
+}
+
+func TestPopulateHazardsRoyaleIsReproducibleFromSeed(t *testing.T) {
+	run := func(seed int64) []rules.Point {
+		b := rules.NewBoardState(11, 11)
+		s := settings.NewSettingsWithParams(rules.ParamShrinkEveryNTurns, "5").WithSeed(seed)
+		for turn := 0; turn < 100; turn++ {
+			_, err := PopulateHazardsRoyale(b.WithTurn(turn), s, mockSnakeMoves())
+			require.NoError(t, err)
+		}
+		return b.Hazards
+	}
+
+	hazards := run(12345)
+	require.NotEmpty(t, hazards)
+	require.Equal(t, hazards, run(12345))
+}
+
+// Checks that snakes take hazard damage inside the shrunken area
+var royaleCaseHazardDamage = gameTestCase{
+	"Royale Case Hazard Damage",
+	&rules.BoardState{
+		Width:  11,
+		Height: 11,
+		Turn:   3,
+		Snakes: []rules.Snake{
+			{
+				ID:     "one",
+				Body:   []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 2}},
+				Health: 100,
+			},
+			{
+				ID:     "two",
+				Body:   []rules.Point{{X: 3, Y: 4}, {X: 3, Y: 3}},
+				Health: 100,
+			},
+		},
+		Food:    []rules.Point{},
+		Hazards: []rules.Point{{X: 0, Y: 1}},
+	},
+	[]SnakeMove{
+		{ID: "one", Move: rules.MoveLeft},
+		{ID: "two", Move: rules.MoveUp},
+	},
+	nil,
+	&rules.BoardState{
+		Width:  11,
+		Height: 11,
+		Snakes: []rules.Snake{
+			{
+				ID:     "one",
+				Body:   []rules.Point{{X: 0, Y: 1}, {X: 1, Y: 1}},
+				Health: 85,
+			},
+			{
+				ID:     "two",
+				Body:   []rules.Point{{X: 3, Y: 5}, {X: 3, Y: 4}},
+				Health: 99,
+			},
+		},
+		Food: []rules.Point{},
+	},
+}
+
+func TestRoyaleCreateNextBoardState(t *testing.T) {
+	cases := []gameTestCase{
+		standardCaseErrNoMoveFound,
+		standardCaseErrZeroLengthSnake,
+		standardCaseMoveEatAndGrow,
+		standardMoveAndCollideMAD,
+		royaleCaseHazardDamage,
+	}
+	s := settings.NewSettingsWithParams(
+		rules.ParamHazardDamagePerTurn, "14",
+		rules.ParamShrinkEveryNTurns, "1",
+	).WithRand(rules.MinRand)
+	r := getRoyaleRuleset(s)
+	for _, gc := range cases {
+		gc.requireValidNextState(t, r)
+		// also test a pipeline with the same settings
+		gc.requireValidNextState(t, NewRulesetBuilder().WithSettings(s).PipelineRuleset(rules.GameTypeRoyale, NewPipeline(royaleRulesetStages...)))
+	}
+}
@@ -73,10 +73,23 @@ func (rb rulesetBuilder) NamedRuleset(name string) Ruleset {
 		stages = append(stages, StageGameOverStandard)
 	}
 
-	if name == rules.GameTypeStandard {
+	switch name {
+	case rules.GameTypeStandard:
 		stages = append(stages, standardRulesetStages[1:]...)
-	} else if name == rules.GameTypeSolo {
+	case rules.GameTypeSolo:
 		stages = soloRulesetStages
+	case rules.GameTypeWrapped:
+		stages = append(stages, wrappedRulesetStages[1:]...)
+	case rules.GameTypeRoyale:
+		stages = append(stages, royaleRulesetStages[1:]...)
+	case rules.GameTypeConstrictor:
+		stages = append(stages, constrictorRulesetStages[1:]...)
+	case rules.GameTypeSquad:
+		if !rb.solo {
+			// Allies win together, so the game is over once one squad remains
+			stages[0] = StageGameOverSquad
+		}
+		stages = append(stages, squadRulesetStages[1:]...)
 	}
 
 	return rb.PipelineRuleset(name, NewPipeline(stages...))
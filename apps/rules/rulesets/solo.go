@@ -7,8 +7,10 @@ import (
 
 var soloRulesetStages = []string{
 	StageGameOverSoloSnake,
+	StageGameOverMaxTurns,
 	StageMovementStandard,
 	StageStarvationStandard,
+	StageHazardDamageStandard,
 	StageFeedSnakesStandard,
 	StageEliminationStandard,
 }
@@ -0,0 +1,120 @@
+package rulesets
+
+import (
+	"rules"
+	"rules/settings"
+)
+
+var squadRulesetStages = []string{
+	StageGameOverSquad,
+	StageGameOverMaxTurns,
+	StageMovementStandard,
+	StageStarvationStandard,
+	StageHazardDamageStandard,
+	StageFeedSnakesStandard,
+	StageEliminationStandard,
+	StageEliminationResurrectSquadCollisions,
+	StageModifySnakesShareAttributes,
+}
+
+// areSnakesOnSameSquad reports whether two snakes are allies.
+// Snakes without a squad have no allies.
+func areSnakesOnSameSquad(snake *rules.Snake, other *rules.Snake) bool {
+	return snake.Squad != "" && snake.Squad == other.Squad
+}
+
+// GameOverSquad ends the game once at most one squad of allied snakes remains.
+// Snakes without a squad are treated as a squad of one.
+func GameOverSquad(b *rules.BoardState, settings settings.Settings, moves []SnakeMove) (bool, error) {
+	numSquadsRemaining := 0
+	squadsSeen := map[string]bool{}
+	for i := 0; i < len(b.Snakes); i++ {
+		if b.Snakes[i].EliminatedCause != rules.NotEliminated {
+			continue
+		}
+		squad := b.Snakes[i].Squad
+		if squad == "" || !squadsSeen[squad] {
+			squadsSeen[squad] = true
+			numSquadsRemaining++
+		}
+	}
+	return numSquadsRemaining <= 1, nil
+}
+
+// ResurrectSnakesSquad undoes body collision eliminations between allies, so
+// snakes on the same squad can pass through each other's bodies.
+// Head-to-head collisions between allies are still resolved as normal.
+func ResurrectSnakesSquad(b *rules.BoardState, settings settings.Settings, moves []SnakeMove) (bool, error) {
+	if IsInitialization(b, settings, moves) {
+		return false, nil
+	}
+	if !settings.Bool(rules.ParamAllowBodyCollisions, true) {
+		return false, nil
+	}
+
+	snakesByID := make(map[string]*rules.Snake, len(b.Snakes))
+	for i := 0; i < len(b.Snakes); i++ {
+		snakesByID[b.Snakes[i].ID] = &b.Snakes[i]
+	}
+
+	for i := 0; i < len(b.Snakes); i++ {
+		snake := &b.Snakes[i]
+		if snake.EliminatedCause != rules.EliminatedByCollision || snake.EliminatedOnTurn != b.Turn+1 {
+			continue
+		}
+		if snake.EliminatedBy == "" {
+			return false, rules.RulesetError("snake eliminated by collision and EliminatedBy is not set")
+		}
+		other, ok := snakesByID[snake.EliminatedBy]
+		if ok && snake.ID != other.ID && areSnakesOnSameSquad(snake, other) {
+			rules.EliminateSnake(snake, rules.NotEliminated, "", 0)
+		}
+	}
+	return false, nil
+}
+
+// ShareAttributesSquad optionally syncs health and length between allies, and
+// eliminates every member of a squad once one of them has been eliminated.
+func ShareAttributesSquad(b *rules.BoardState, settings settings.Settings, moves []SnakeMove) (bool, error) {
+	sharedElimination := settings.Bool(rules.ParamSharedElimination, true)
+	sharedHealth := settings.Bool(rules.ParamSharedHealth, false)
+	sharedLength := settings.Bool(rules.ParamSharedLength, false)
+	if !(sharedElimination || sharedHealth || sharedLength) {
+		return false, nil
+	}
+
+	for i := 0; i < len(b.Snakes); i++ {
+		snake := &b.Snakes[i]
+		if snake.EliminatedCause != rules.NotEliminated {
+			continue
+		}
+
+		for j := 0; j < len(b.Snakes); j++ {
+			other := &b.Snakes[j]
+			if i == j || !areSnakesOnSameSquad(snake, other) {
+				continue
+			}
+
+			if sharedElimination && other.EliminatedCause != rules.NotEliminated {
+				// EliminatedBy is intentionally left empty because there might be multiple culprits.
+				rules.EliminateSnake(snake, rules.EliminatedBySquad, "", b.Turn+1)
+				break
+			}
+			if other.EliminatedCause != rules.NotEliminated {
+				continue
+			}
+			if sharedHealth && snake.Health < other.Health {
+				snake.Health = other.Health
+			}
+			if sharedLength {
+				if len(snake.Body) == 0 || len(other.Body) == 0 {
+					return false, rules.ErrorZeroLengthSnake
+				}
+				for len(snake.Body) < len(other.Body) {
+					growSnake(snake)
+				}
+			}
+		}
+	}
+	return false, nil
+}
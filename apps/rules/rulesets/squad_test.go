@@ -0,0 +1,234 @@
+package rulesets
+
+import (
+	"rules"
+	"rules/settings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getSquadRuleset(settings settings.Settings) Ruleset {
+	return NewRulesetBuilder().WithSettings(settings).NamedRuleset(rules.GameTypeSquad)
+}
+
+func TestSquadName(t *testing.T) {
+	r := getSquadRuleset(settings.Settings{})
+	require.Equal(t, "squad", r.Name())
+}
+
+func TestAreSnakesOnSameSquad(t *testing.T) {
+	tests := []struct {
+		snake    rules.Snake
+		other    rules.Snake
+		expected bool
+	}{
+		{rules.Snake{ID: "a", Squad: "red"}, rules.Snake{ID: "b", Squad: "red"}, true},
+		{rules.Snake{ID: "a", Squad: "red"}, rules.Snake{ID: "b", Squad: "blue"}, false},
+		{rules.Snake{ID: "a", Squad: "red"}, rules.Snake{ID: "b"}, false},
+		{rules.Snake{ID: "a"}, rules.Snake{ID: "b"}, false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, areSnakesOnSameSquad(&test.snake, &test.other))
+	}
+}
+
+func TestResurrectSnakesSquad(t *testing.T) {
+	newBoard := func() *rules.BoardState {
+		return &rules.BoardState{
+			Snakes: []rules.Snake{
+				{ID: "ally-body", Squad: "red", EliminatedCause: rules.EliminatedByCollision, EliminatedBy: "ally-other", EliminatedOnTurn: 1},
+				{ID: "ally-other", Squad: "red"},
+				{ID: "enemy-body", Squad: "red", EliminatedCause: rules.EliminatedByCollision, EliminatedBy: "enemy", EliminatedOnTurn: 1},
+				{ID: "enemy", Squad: "blue"},
+				{ID: "ally-head", Squad: "red", EliminatedCause: rules.EliminatedByHeadToHeadCollision, EliminatedBy: "ally-other", EliminatedOnTurn: 1},
+				{ID: "ally-old", Squad: "red", EliminatedCause: rules.EliminatedByCollision, EliminatedBy: "ally-other", EliminatedOnTurn: 0},
+			},
+		}
+	}
+
+	b := newBoard()
+	_, err := ResurrectSnakesSquad(b, settings.Settings{}, mockSnakeMoves())
+	require.NoError(t, err)
+	require.Equal(t, rules.NotEliminated, b.Snakes[0].EliminatedCause)
+	require.Equal(t, "", b.Snakes[0].EliminatedBy)
+	require.Equal(t, 0, b.Snakes[0].EliminatedOnTurn)
+	require.Equal(t, rules.EliminatedByCollision, b.Snakes[2].EliminatedCause)
+	require.Equal(t, rules.EliminatedByHeadToHeadCollision, b.Snakes[4].EliminatedCause)
+	require.Equal(t, rules.EliminatedByCollision, b.Snakes[5].EliminatedCause)
+
+	// Disabling body collisions leaves eliminations alone
+	b = newBoard()
+	s := settings.NewSettingsWithParams(rules.ParamAllowBodyCollisions, "false")
+	_, err = ResurrectSnakesSquad(b, s, mockSnakeMoves())
+	require.NoError(t, err)
+	require.Equal(t, newBoard(), b)
+
+	// Missing culprit is an error
+	b = newBoard()
+	b.Snakes[0].EliminatedBy = ""
+	_, err = ResurrectSnakesSquad(b, settings.Settings{}, mockSnakeMoves())
+	require.Error(t, err)
+}
+
+func TestShareAttributesSquad(t *testing.T) {
+	newBoard := func() *rules.BoardState {
+		return &rules.BoardState{
+			Turn: 4,
+			Snakes: []rules.Snake{
+				{ID: "red-1", Squad: "red", Health: 50, Body: []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 2}}},
+				{ID: "red-2", Squad: "red", Health: 90, Body: []rules.Point{{X: 3, Y: 1}, {X: 3, Y: 2}, {X: 3, Y: 3}}},
+				{ID: "blue-1", Squad: "blue", Health: 100, Body: []rules.Point{{X: 5, Y: 1}, {X: 5, Y: 2}, {X: 5, Y: 3}, {X: 5, Y: 4}}},
+				{ID: "blue-2", Squad: "blue", Health: 20, Body: []rules.Point{{X: 7, Y: 1}}, EliminatedCause: rules.EliminatedByOutOfBounds, EliminatedOnTurn: 5},
+				{ID: "solo", Health: 10, Body: []rules.Point{{X: 9, Y: 1}}},
+			},
+		}
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		b := newBoard()
+		_, err := ShareAttributesSquad(b, settings.Settings{}, mockSnakeMoves())
+		require.NoError(t, err)
+		require.Equal(t, rules.NotEliminated, b.Snakes[0].EliminatedCause)
+		require.Equal(t, 50, b.Snakes[0].Health)
+		require.Len(t, b.Snakes[0].Body, 2)
+		require.Equal(t, rules.EliminatedBySquad, b.Snakes[2].EliminatedCause)
+		require.Equal(t, "", b.Snakes[2].EliminatedBy)
+		require.Equal(t, 5, b.Snakes[2].EliminatedOnTurn)
+		require.Equal(t, rules.NotEliminated, b.Snakes[4].EliminatedCause)
+	})
+
+	t.Run("shared health and length", func(t *testing.T) {
+		b := newBoard()
+		s := settings.NewSettingsWithParams(
+			rules.ParamSharedElimination, "false",
+			rules.ParamSharedHealth, "true",
+			rules.ParamSharedLength, "true",
+		)
+		_, err := ShareAttributesSquad(b, s, mockSnakeMoves())
+		require.NoError(t, err)
+		require.Equal(t, 90, b.Snakes[0].Health)
+		require.Equal(t, []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 2}}, b.Snakes[0].Body)
+		require.Equal(t, 90, b.Snakes[1].Health)
+		require.Len(t, b.Snakes[1].Body, 3)
+		require.Equal(t, rules.NotEliminated, b.Snakes[2].EliminatedCause)
+		require.Equal(t, 100, b.Snakes[2].Health)
+		require.Len(t, b.Snakes[2].Body, 4)
+		require.Equal(t, 10, b.Snakes[4].Health)
+	})
+}
+
+func TestSquadIsGameOver(t *testing.T) {
+	tests := []struct {
+		snakes   []rules.Snake
+		expected bool
+	}{
+		{[]rules.Snake{{Squad: "red"}, {Squad: "red"}}, true},
+		{[]rules.Snake{{Squad: "red"}, {Squad: "blue"}}, false},
+		{[]rules.Snake{{Squad: "red"}, {}}, false},
+		{[]rules.Snake{{Squad: "red"}, {Squad: "blue", EliminatedCause: rules.EliminatedByOutOfBounds}}, true},
+		{[]rules.Snake{{Squad: "red"}, {Squad: "red"}, {Squad: "blue"}, {Squad: "blue", EliminatedCause: rules.EliminatedByOutOfBounds}}, false},
+	}
+
+	r := getSquadRuleset(settings.Settings{})
+	for _, test := range tests {
+		b := rules.NewBoardState(11, 11).WithSnakes(test.snakes)
+		actual, _, err := r.Execute(b, nil)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, actual)
+	}
+}
+
+// Checks that allies pass through each other's bodies while enemies still collide
+var squadCaseBodyCollisions = gameTestCase{
+	"Squad Case Body Collisions",
+	&rules.BoardState{
+		Width:  10,
+		Height: 10,
+		Snakes: []rules.Snake{
+			{
+				ID:     "red-1",
+				Squad:  "red",
+				Body:   []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}},
+				Health: 100,
+			},
+			{
+				ID:     "red-2",
+				Squad:  "red",
+				Body:   []rules.Point{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}},
+				Health: 100,
+			},
+			{
+				ID:     "blue-1",
+				Squad:  "blue",
+				Body:   []rules.Point{{X: 3, Y: 1}, {X: 4, Y: 1}, {X: 5, Y: 1}},
+				Health: 100,
+			},
+			{
+				ID:     "blue-2",
+				Squad:  "blue",
+				Body:   []rules.Point{{X: 8, Y: 8}, {X: 8, Y: 7}, {X: 8, Y: 6}},
+				Health: 100,
+			},
+		},
+		Food: []rules.Point{},
+	},
+	[]SnakeMove{
+		{ID: "red-1", Move: rules.MoveRight},
+		{ID: "red-2", Move: rules.MoveLeft},
+		{ID: "blue-1", Move: rules.MoveUp},
+		{ID: "blue-2", Move: rules.MoveUp},
+	},
+	nil,
+	&rules.BoardState{
+		Width:  10,
+		Height: 10,
+		Snakes: []rules.Snake{
+			{
+				ID:     "red-1",
+				Squad:  "red",
+				Body:   []rules.Point{{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+				Health: 99,
+			},
+			{
+				ID:     "red-2",
+				Squad:  "red",
+				Body:   []rules.Point{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}},
+				Health: 99,
+			},
+			{
+				ID:               "blue-1",
+				Squad:            "blue",
+				Body:             []rules.Point{{X: 3, Y: 2}, {X: 3, Y: 1}, {X: 4, Y: 1}},
+				Health:           99,
+				EliminatedCause:  rules.EliminatedByCollision,
+				EliminatedBy:     "red-2",
+				EliminatedOnTurn: 1,
+			},
+			{
+				ID:               "blue-2",
+				Squad:            "blue",
+				Body:             []rules.Point{{X: 8, Y: 9}, {X: 8, Y: 8}, {X: 8, Y: 7}},
+				Health:           99,
+				EliminatedCause:  rules.EliminatedBySquad,
+				EliminatedOnTurn: 1,
+			},
+		},
+		Food: []rules.Point{},
+	},
+}
+
+func TestSquadCreateNextBoardState(t *testing.T) {
+	cases := []gameTestCase{
+		standardCaseErrNoMoveFound,
+		standardCaseErrZeroLengthSnake,
+		standardCaseMoveEatAndGrow,
+		squadCaseBodyCollisions,
+	}
+	r := getSquadRuleset(settings.Settings{})
+	for _, gc := range cases {
+		gc.requireValidNextState(t, r)
+		// also test a pipeline with the same settings
+		gc.requireValidNextState(t, NewRulesetBuilder().PipelineRuleset(rules.GameTypeSquad, NewPipeline(squadRulesetStages...)))
+	}
+}
@@ -8,8 +8,10 @@ import (
 
 var standardRulesetStages = []string{
 	StageGameOverStandard,
+	StageGameOverMaxTurns,
 	StageMovementStandard,
 	StageStarvationStandard,
+	StageHazardDamageStandard,
 	StageFeedSnakesStandard,
 	StageEliminationStandard,
 }
@@ -127,6 +129,51 @@ func ReduceSnakeHealthStandard(b *rules.BoardState, settings settings.Settings,
 	return false, nil
 }
 
+func DamageHazardsStandard(b *rules.BoardState, settings settings.Settings, moves []SnakeMove) (bool, error) {
+	if IsInitialization(b, settings, moves) {
+		return false, nil
+	}
+	hazardDamage := settings.Int(rules.ParamHazardDamagePerTurn, 0)
+	if hazardDamage <= 0 {
+		return false, nil
+	}
+	for i := 0; i < len(b.Snakes); i++ {
+		snake := &b.Snakes[i]
+		if snake.EliminatedCause != rules.NotEliminated || len(snake.Body) == 0 {
+			continue
+		}
+		head := snake.Body[0]
+		for _, p := range b.Hazards {
+			if head != p {
+				continue
+			}
+
+			// If there's food in this square, don't reduce health
+			foundFood := false
+			for _, food := range b.Food {
+				if p == food {
+					foundFood = true
+					break
+				}
+			}
+			if foundFood {
+				continue
+			}
+
+			// Snake is in a hazard, reduce health
+			snake.Health = snake.Health - hazardDamage
+			if snake.Health < 0 {
+				snake.Health = 0
+			}
+			if snakeIsOutOfHealth(snake) {
+				rules.EliminateSnake(snake, rules.EliminatedByHazard, "", b.Turn+1)
+				break
+			}
+		}
+	}
+	return false, nil
+}
+
 func EliminateSnakesStandard(b *rules.BoardState, settings settings.Settings, moves []SnakeMove) (bool, error) {
 	if IsInitialization(b, settings, moves) {
 		return false, nil
@@ -819,6 +819,59 @@ func TestReduceSnakeHealth(t *testing.T) {
 	require.Equal(t, b.Snakes[2].Health, 50)
 }
 
+func TestDamageHazards(t *testing.T) {
+	b := &rules.BoardState{
+		Turn: 41,
+		Snakes: []rules.Snake{
+			{
+				ID:     "in_hazard",
+				Body:   []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 2}},
+				Health: 99,
+			},
+			{
+				ID:     "in_hazard_with_food",
+				Body:   []rules.Point{{X: 2, Y: 2}, {X: 2, Y: 3}},
+				Health: 99,
+			},
+			{
+				ID:     "body_in_hazard",
+				Body:   []rules.Point{{X: 4, Y: 4}, {X: 1, Y: 1}},
+				Health: 99,
+			},
+			{
+				ID:     "out_of_health",
+				Body:   []rules.Point{{X: 3, Y: 3}, {X: 3, Y: 4}},
+				Health: 10,
+			},
+			{
+				ID:              "eliminated",
+				Body:            []rules.Point{{X: 1, Y: 1}, {X: 1, Y: 2}},
+				Health:          50,
+				EliminatedCause: rules.EliminatedByCollision,
+			},
+		},
+		Food:    []rules.Point{{X: 2, Y: 2}},
+		Hazards: []rules.Point{{X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}},
+	}
+
+	s := settings.NewSettingsWithParams(rules.ParamHazardDamagePerTurn, "14")
+	_, err := DamageHazardsStandard(b, s, mockSnakeMoves())
+	require.NoError(t, err)
+	require.Equal(t, 85, b.Snakes[0].Health)
+	require.Equal(t, 99, b.Snakes[1].Health)
+	require.Equal(t, 99, b.Snakes[2].Health)
+	require.Equal(t, 0, b.Snakes[3].Health)
+	require.Equal(t, rules.EliminatedByHazard, b.Snakes[3].EliminatedCause)
+	require.Equal(t, 42, b.Snakes[3].EliminatedOnTurn)
+	require.Equal(t, 50, b.Snakes[4].Health)
+	require.Equal(t, rules.EliminatedByCollision, b.Snakes[4].EliminatedCause)
+
+	// No damage is applied when the setting is missing
+	_, err = DamageHazardsStandard(b, settings.Settings{}, mockSnakeMoves())
+	require.NoError(t, err)
+	require.Equal(t, 85, b.Snakes[0].Health)
+}
+
 func TestSnakeIsOutOfHealth(t *testing.T) {
 	tests := []struct {
 		Health   int
@@ -1354,6 +1407,7 @@ func TestIsGameOver(t *testing.T) {
 		{[]rules.Snake{{}}, true},
 		{[]rules.Snake{{}, {}}, false},
 		{[]rules.Snake{{}, {}, {}, {}, {}}, false},
+		{[]rules.Snake{{Squad: "red"}, {Squad: "red"}}, false},
 		{
 			[]rules.Snake{
 				{EliminatedCause: rules.EliminatedByCollision},
@@ -0,0 +1,236 @@
+package rulesets
+
+import (
+	"rules"
+	"rules/settings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getWrappedRuleset(settings settings.Settings) Ruleset {
+	return NewRulesetBuilder().WithSettings(settings).NamedRuleset(rules.GameTypeWrapped)
+}
+
+func TestWrappedName(t *testing.T) {
+	r := getWrappedRuleset(settings.Settings{})
+	require.Equal(t, "wrapped", r.Name())
+}
+
+func TestWrap(t *testing.T) {
+	require.Equal(t, 10, wrap(-1, 0, 10))
+	require.Equal(t, 0, wrap(11, 0, 10))
+	require.Equal(t, 5, wrap(5, 0, 10))
+	require.Equal(t, 0, wrap(0, 0, 10))
+	require.Equal(t, 10, wrap(10, 0, 10))
+}
+
+func TestMoveSnakesWrapped(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []rules.Point
+		move     string
+		expected []rules.Point
+	}{
+		{
+			"no wrap",
+			[]rules.Point{{X: 5, Y: 5}, {X: 5, Y: 4}},
+			rules.MoveUp,
+			[]rules.Point{{X: 5, Y: 6}, {X: 5, Y: 5}},
+		},
+		{
+			"wrap top",
+			[]rules.Point{{X: 5, Y: 10}, {X: 5, Y: 9}},
+			rules.MoveUp,
+			[]rules.Point{{X: 5, Y: 0}, {X: 5, Y: 10}},
+		},
+		{
+			"wrap bottom",
+			[]rules.Point{{X: 5, Y: 0}, {X: 5, Y: 1}},
+			rules.MoveDown,
+			[]rules.Point{{X: 5, Y: 10}, {X: 5, Y: 0}},
+		},
+		{
+			"wrap left",
+			[]rules.Point{{X: 0, Y: 5}, {X: 1, Y: 5}},
+			rules.MoveLeft,
+			[]rules.Point{{X: 10, Y: 5}, {X: 0, Y: 5}},
+		},
+		{
+			"wrap right",
+			[]rules.Point{{X: 10, Y: 5}, {X: 9, Y: 5}},
+			rules.MoveRight,
+			[]rules.Point{{X: 0, Y: 5}, {X: 10, Y: 5}},
+		},
+		// Invalid moves fall back to getDefaultMove, which has to read the
+		// direction of travel from a neck on the opposite edge of the board.
+		{
+			"default move after wrapping up",
+			[]rules.Point{{X: 5, Y: 0}, {X: 5, Y: 10}},
+			"invalid",
+			[]rules.Point{{X: 5, Y: 1}, {X: 5, Y: 0}},
+		},
+		{
+			"default move after wrapping down",
+			[]rules.Point{{X: 5, Y: 10}, {X: 5, Y: 0}},
+			"",
+			[]rules.Point{{X: 5, Y: 9}, {X: 5, Y: 10}},
+		},
+		{
+			"default move after wrapping right",
+			[]rules.Point{{X: 0, Y: 5}, {X: 10, Y: 5}},
+			"invalid",
+			[]rules.Point{{X: 1, Y: 5}, {X: 0, Y: 5}},
+		},
+		{
+			"default move after wrapping left",
+			[]rules.Point{{X: 10, Y: 5}, {X: 0, Y: 5}},
+			"",
+			[]rules.Point{{X: 9, Y: 5}, {X: 10, Y: 5}},
+		},
+	}
+
+	r := getWrappedRuleset(settings.Settings{})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := rules.NewBoardState(11, 11).WithSnakes([]rules.Snake{
+				{ID: "one", Body: test.body, Health: 100},
+			})
+			moves := []SnakeMove{{ID: "one", Move: test.move}}
+
+			_, err := MoveSnakesWrapped(b, r.Settings(), moves)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, b.Snakes[0].Body)
+		})
+	}
+}
+
+// Checks that snakes crossing every edge of the board are not eliminated
+var wrappedCaseMoveThroughEdges = gameTestCase{
+	"Wrapped Case Move Through Edges",
+	&rules.BoardState{
+		Width:  11,
+		Height: 11,
+		Snakes: []rules.Snake{
+			{
+				ID:     "up",
+				Body:   []rules.Point{{X: 1, Y: 10}, {X: 1, Y: 9}, {X: 1, Y: 8}},
+				Health: 100,
+			},
+			{
+				ID:     "down",
+				Body:   []rules.Point{{X: 3, Y: 0}, {X: 3, Y: 1}, {X: 3, Y: 2}},
+				Health: 100,
+			},
+			{
+				ID:     "left",
+				Body:   []rules.Point{{X: 0, Y: 5}, {X: 1, Y: 5}, {X: 2, Y: 5}},
+				Health: 100,
+			},
+			{
+				ID:     "right",
+				Body:   []rules.Point{{X: 10, Y: 7}, {X: 9, Y: 7}, {X: 8, Y: 7}},
+				Health: 100,
+			},
+		},
+		Food: []rules.Point{{X: 0, Y: 7}},
+	},
+	[]SnakeMove{
+		{ID: "up", Move: rules.MoveUp},
+		{ID: "down", Move: rules.MoveDown},
+		{ID: "left", Move: rules.MoveLeft},
+		{ID: "right", Move: rules.MoveRight},
+	},
+	nil,
+	&rules.BoardState{
+		Width:  11,
+		Height: 11,
+		Snakes: []rules.Snake{
+			{
+				ID:     "up",
+				Body:   []rules.Point{{X: 1, Y: 0}, {X: 1, Y: 10}, {X: 1, Y: 9}},
+				Health: 99,
+			},
+			{
+				ID:     "down",
+				Body:   []rules.Point{{X: 3, Y: 10}, {X: 3, Y: 0}, {X: 3, Y: 1}},
+				Health: 99,
+			},
+			{
+				ID:     "left",
+				Body:   []rules.Point{{X: 10, Y: 5}, {X: 0, Y: 5}, {X: 1, Y: 5}},
+				Health: 99,
+			},
+			{
+				ID:     "right",
+				Body:   []rules.Point{{X: 0, Y: 7}, {X: 10, Y: 7}, {X: 9, Y: 7}, {X: 9, Y: 7}},
+				Health: 100,
+			},
+		},
+		Food: []rules.Point{},
+	},
+}
+
+// Checks that a head-to-head across the board edge is still resolved
+var wrappedCaseHeadToHeadAcrossEdge = gameTestCase{
+	"Wrapped Case Head To Head Across Edge",
+	&rules.BoardState{
+		Width:  11,
+		Height: 11,
+		Snakes: []rules.Snake{
+			{
+				ID:     "long",
+				Body:   []rules.Point{{X: 10, Y: 3}, {X: 9, Y: 3}, {X: 8, Y: 3}, {X: 7, Y: 3}},
+				Health: 100,
+			},
+			{
+				ID:     "short",
+				Body:   []rules.Point{{X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}},
+				Health: 100,
+			},
+		},
+		Food: []rules.Point{},
+	},
+	[]SnakeMove{
+		{ID: "long", Move: rules.MoveRight},
+		{ID: "short", Move: rules.MoveLeft},
+	},
+	nil,
+	&rules.BoardState{
+		Width:  11,
+		Height: 11,
+		Snakes: []rules.Snake{
+			{
+				ID:     "long",
+				Body:   []rules.Point{{X: 0, Y: 3}, {X: 10, Y: 3}, {X: 9, Y: 3}, {X: 8, Y: 3}},
+				Health: 99,
+			},
+			{
+				ID:               "short",
+				Body:             []rules.Point{{X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 3}},
+				Health:           99,
+				EliminatedCause:  rules.EliminatedByHeadToHeadCollision,
+				EliminatedBy:     "long",
+				EliminatedOnTurn: 1,
+			},
+		},
+		Food: []rules.Point{},
+	},
+}
+
+func TestWrappedCreateNextBoardState(t *testing.T) {
+	cases := []gameTestCase{
+		standardCaseErrNoMoveFound,
+		standardCaseErrZeroLengthSnake,
+		standardCaseMoveEatAndGrow,
+		standardMoveAndCollideMAD,
+		wrappedCaseMoveThroughEdges,
+		wrappedCaseHeadToHeadAcrossEdge,
+	}
+	r := getWrappedRuleset(settings.Settings{})
+	for _, gc := range cases {
+		gc.requireValidNextState(t, r)
+		// also test a pipeline with the same settings
+		gc.requireValidNextState(t, NewRulesetBuilder().PipelineRuleset(rules.GameTypeWrapped, NewPipeline(wrappedRulesetStages...)))
+	}
+}
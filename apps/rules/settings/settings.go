@@ -1,8 +1,12 @@
 package settings
 
 import (
-		"strconv"
-		"rules"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"rules"
 )
 
 // Settings contains all settings relevant to a game.
@@ -91,3 +95,68 @@ func (settings Settings) Int(paramName string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// String returns the raw string value for the specified parameter.
+// If the parameter doesn't exist, the default value will be returned.
+func (settings Settings) String(paramName string, defaultValue string) string {
+	if val, ok := settings.rawValues[paramName]; ok {
+		return val
+	}
+	return defaultValue
+}
+
+// intRanges lists the known integer parameters along with their inclusive bounds,
+// in the order they are validated.
+var intRanges = []struct {
+	paramName string
+	min, max  int
+}{
+	{rules.ParamFoodSpawnChance, 0, 100},
+	{rules.ParamMinimumFood, 0, math.MaxInt32},
+	{rules.ParamHazardDamagePerTurn, 0, rules.SnakeMaxHealth},
+	{rules.ParamShrinkEveryNTurns, 0, math.MaxInt32},
+	{rules.ParamMaxTurns, 0, math.MaxInt32},
+}
+
+// boolParams lists the known boolean parameters.
+var boolParams = []string{
+	rules.ParamAllowBodyCollisions,
+	rules.ParamSharedElimination,
+	rules.ParamSharedHealth,
+	rules.ParamSharedLength,
+}
+
+// Validate checks that every known parameter which has been set holds a usable value.
+// Unknown parameters are ignored.
+func (settings Settings) Validate() error {
+	for _, r := range intRanges {
+		val, ok := settings.rawValues[r.paramName]
+		if !ok {
+			continue
+		}
+		i, err := strconv.Atoi(val)
+		if err != nil {
+			return fmt.Errorf("%w: %s must be an integer, got %q", rules.ErrorInvalidSetting, r.paramName, val)
+		}
+		if i < r.min || i > r.max {
+			return fmt.Errorf("%w: %s must be between %d and %d, got %d", rules.ErrorInvalidSetting, r.paramName, r.min, r.max, i)
+		}
+	}
+	for _, paramName := range boolParams {
+		val, ok := settings.rawValues[paramName]
+		if ok && val != "true" && val != "false" {
+			return fmt.Errorf("%w: %s must be true or false, got %q", rules.ErrorInvalidSetting, paramName, val)
+		}
+	}
+	for _, tieBreak := range strings.Split(settings.String(rules.ParamTieBreak, ""), ",") {
+		switch strings.TrimSpace(tieBreak) {
+		case "", rules.TieBreakLength, rules.TieBreakHealth, rules.TieBreakKills:
+		default:
+			return fmt.Errorf("%w: %s must be a comma separated list of %s, %s or %s, got %q", rules.ErrorInvalidSetting, rules.ParamTieBreak, rules.TieBreakLength, rules.TieBreakHealth, rules.TieBreakKills, tieBreak)
+		}
+	}
+	if settings.String(rules.ParamGameType, "") == rules.GameTypeRoyale && settings.Int(rules.ParamShrinkEveryNTurns, 0) < 1 {
+		return fmt.Errorf("%w: %s must be at least 1 in %s games", rules.ErrorInvalidSetting, rules.ParamShrinkEveryNTurns, rules.GameTypeRoyale)
+	}
+	return nil
+}
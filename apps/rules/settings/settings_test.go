@@ -1,6 +1,7 @@
 package settings_test
 
 import (
+	"rules"
 	"rules/settings"
 	"testing"
 
@@ -25,7 +26,63 @@ func TestSettings(t *testing.T) {
 	assert.Equal(t, false, testSettings.Bool("invalidSetting", true))
 	assert.Equal(t, true, testSettings.Bool("boolSetting", true))
 
+	assert.Equal(t, "default", testSettings.String("missingStringSetting", "default"))
+	assert.Equal(t, "abcd", testSettings.String("invalidSetting", "default"))
+	assert.Equal(t, "1234", testSettings.String("intSetting", "default"))
+
 	assert.Equal(t, 4567, settings.NewSettingsWithParams("newIntSetting").Int("newIntSetting", 4567))
 	assert.Equal(t, 1234, settings.NewSettingsWithParams("newIntSetting", "1234").Int("newIntSetting", 4567))
 	assert.Equal(t, 4567, settings.NewSettingsWithParams("x", "y", "newIntSetting").Int("newIntSetting", 4567))
 }
+
+func TestSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []string
+		valid  bool
+	}{
+		{"empty", nil, true},
+		{"unknown params are ignored", []string{"unknown", "abcd"}, true},
+		{"all valid", []string{
+			rules.ParamFoodSpawnChance, "15",
+			rules.ParamMinimumFood, "1",
+			rules.ParamHazardDamagePerTurn, "14",
+			rules.ParamShrinkEveryNTurns, "25",
+			rules.ParamMaxTurns, "0",
+			rules.ParamSharedHealth, "false",
+		}, true},
+		{"non-integer", []string{rules.ParamMinimumFood, "abcd"}, false},
+		{"food spawn chance above 100", []string{rules.ParamFoodSpawnChance, "101"}, false},
+		{"negative minimum food", []string{rules.ParamMinimumFood, "-1"}, false},
+		{"negative hazard damage", []string{rules.ParamHazardDamagePerTurn, "-5"}, false},
+		{"zero shrink interval", []string{rules.ParamShrinkEveryNTurns, "0"}, true},
+		{"zero shrink interval in royale", []string{rules.ParamGameType, rules.GameTypeRoyale, rules.ParamShrinkEveryNTurns, "0"}, false},
+		{"missing shrink interval in royale", []string{rules.ParamGameType, rules.GameTypeRoyale}, false},
+		{"negative max turns", []string{rules.ParamMaxTurns, "-1"}, false},
+		{"non-boolean", []string{rules.ParamAllowBodyCollisions, "yes"}, false},
+		{"tie-break list", []string{rules.ParamTieBreak, "kills, length"}, true},
+		{"unknown tie-break", []string{rules.ParamTieBreak, "length,speed"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := settings.NewSettingsWithParams(test.params...).Validate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, rules.ErrorInvalidSetting)
+			}
+		})
+	}
+}
+
+func TestSettingsValidateReportsFirstInvalidParam(t *testing.T) {
+	s := settings.NewSettingsWithParams(
+		rules.ParamMaxTurns, "-1",
+		rules.ParamMinimumFood, "-1",
+		rules.ParamFoodSpawnChance, "101",
+	)
+	for i := 0; i < 10; i++ {
+		assert.EqualError(t, s.Validate(), "invalid setting: foodSpawnChance must be between 0 and 100, got 101")
+	}
+}